@@ -0,0 +1,418 @@
+package circuit
+
+import (
+	"fmt"
+
+	"github.com/benluxford/qe/gate"
+	"github.com/benluxford/qe/matrix"
+	"github.com/benluxford/qe/qubit"
+)
+
+// Circuit : An ordered list of operations on a fixed number of bits, the classical bits
+// grow to hold every measurement result. Bits can be grouped into named quantum and classical
+// registers, see Register
+type Circuit struct {
+	bit   int
+	clbit int
+	ops   []Op
+	qregs []Register
+	cregs []Register
+}
+
+// New : Returns a pointer to a new empty Circuit on the given number of bits
+func New(bit int) *Circuit {
+	return &Circuit{bit: bit}
+}
+
+// NumberOfBit : Returns the number of bits in the Circuit
+func (c *Circuit) NumberOfBit() int {
+	return c.bit
+}
+
+// NumberOfClbit : Returns the number of classical bits written by the Circuit
+func (c *Circuit) NumberOfClbit() int {
+	return c.clbit
+}
+
+// Ops : Returns the operations of the Circuit in order
+func (c *Circuit) Ops() []Op {
+	return c.ops
+}
+
+// Len : Returns the number of operations in the Circuit
+func (c *Circuit) Len() int {
+	return len(c.ops)
+}
+
+// Clone : Returns a copy of the Circuit sharing the operations' matrices
+func (c *Circuit) Clone() *Circuit {
+	ops := make([]Op, len(c.ops))
+	copy(ops, c.ops)
+	return &Circuit{c.bit, c.clbit, ops, append([]Register{}, c.qregs...), append([]Register{}, c.cregs...)}
+}
+
+// Empty : Returns a new Circuit with the bits and registers of the Circuit but no operations
+func (c *Circuit) Empty() *Circuit {
+	return &Circuit{c.bit, c.clbit, nil, append([]Register{}, c.qregs...), append([]Register{}, c.cregs...)}
+}
+
+// Append : Returns the Circuit with the operations appended, panics if an Op is out of range
+func (c *Circuit) Append(op ...Op) *Circuit {
+	for _, o := range op {
+		for _, q := range o.Qubits() {
+			if q < 0 || q >= c.bit {
+				panic(fmt.Sprintf("circuit: %v is outside of %d bits", o, c.bit))
+			}
+		}
+		clbits := append([]int{}, o.Clbits...)
+		if o.Condition != nil {
+			clbits = append(clbits, o.Condition.Clbits...)
+		}
+		for _, b := range clbits {
+			if b < 0 {
+				panic(fmt.Sprintf("circuit: %v uses a negative classical bit", o))
+			}
+			if b >= c.clbit {
+				c.clbit = b + 1
+			}
+		}
+		c.ops = append(c.ops, o)
+	}
+	return c
+}
+
+// Compose : Returns the Circuit with all operations of the input appended
+func (c *Circuit) Compose(input *Circuit) *Circuit {
+	return c.Append(input.ops...)
+}
+
+// Counts : Returns the number of operations by name, controls are prefixed with C
+func (c *Circuit) Counts() (counts map[string]int) {
+	counts = map[string]int{}
+	for _, o := range c.ops {
+		counts[o.Label()]++
+	}
+	return
+}
+
+// Depth : Returns the number of layers of operations, operations sharing a bit are in
+// different layers and barriers do not count
+func (c *Circuit) Depth() (depth int) {
+	layer := make([]int, c.bit)
+	for _, o := range c.ops {
+		if o.Name == "Barrier" {
+			continue
+		}
+		qubits := o.Qubits()
+		l := 0
+		for _, q := range qubits {
+			if layer[q] > l {
+				l = layer[q]
+			}
+		}
+		for _, q := range qubits {
+			layer[q] = l + 1
+		}
+		if l+1 > depth {
+			depth = l + 1
+		}
+	}
+	return
+}
+
+// Gate : Appends an arbitrary gate Matrix acting on the given qubits
+func (c *Circuit) Gate(name string, input matrix.Matrix, qubits ...int) *Circuit {
+	return c.Append(Op{Name: name, Targets: qubits, Matrix: input})
+}
+
+// I : Appends an identity on the given qubit
+func (c *Circuit) I(bit int) *Circuit {
+	return c.Gate("I", gate.I(), bit)
+}
+
+// X : Appends a Pauli X on the given qubit
+func (c *Circuit) X(bit int) *Circuit {
+	return c.Gate("X", gate.X(), bit)
+}
+
+// Y : Appends a Pauli Y on the given qubit
+func (c *Circuit) Y(bit int) *Circuit {
+	return c.Gate("Y", gate.Y(), bit)
+}
+
+// Z : Appends a Pauli Z on the given qubit
+func (c *Circuit) Z(bit int) *Circuit {
+	return c.Gate("Z", gate.Z(), bit)
+}
+
+// H : Appends a Hadamard on the given qubit
+func (c *Circuit) H(bit int) *Circuit {
+	return c.Gate("H", gate.H(), bit)
+}
+
+// S : Appends an S gate on the given qubit
+func (c *Circuit) S(bit int) *Circuit {
+	return c.Gate("S", gate.S(), bit)
+}
+
+// T : Appends a T gate on the given qubit
+func (c *Circuit) T(bit int) *Circuit {
+	return c.Gate("T", gate.T(), bit)
+}
+
+// Sdg : Appends the inverse of S on the given qubit
+func (c *Circuit) Sdg(bit int) *Circuit {
+	return c.Gate("Sdg", gate.SDagger(), bit)
+}
+
+// Tdg : Appends the inverse of T on the given qubit
+func (c *Circuit) Tdg(bit int) *Circuit {
+	return c.Gate("Tdg", gate.TDagger(), bit)
+}
+
+// SX : Appends the square root of X on the given qubit
+func (c *Circuit) SX(bit int) *Circuit {
+	return c.Gate("SX", gate.SX(), bit)
+}
+
+// U3 : Appends the single bit gate U3(theta, phi, lambda) on the given qubit
+func (c *Circuit) U3(bit int, theta, phi, lambda float64) *Circuit {
+	return c.Append(Op{
+		Name:    "U3",
+		Targets: []int{bit},
+		Params:  []float64{theta, phi, lambda},
+		Matrix:  gate.U3(theta, phi, lambda),
+	})
+}
+
+// P : Appends the phase rotation diag(1, e^(i phi)) on the given qubit
+func (c *Circuit) P(bit int, phi float64) *Circuit {
+	return c.Append(Op{Name: "P", Targets: []int{bit}, Params: []float64{phi}, Matrix: gate.P(phi)})
+}
+
+// RX : Appends a rotation of theta about the X axis on the given qubit
+func (c *Circuit) RX(bit int, theta float64) *Circuit {
+	return c.Append(Op{Name: "RX", Targets: []int{bit}, Params: []float64{theta}, Matrix: gate.RX(theta)})
+}
+
+// RY : Appends a rotation of theta about the Y axis on the given qubit
+func (c *Circuit) RY(bit int, theta float64) *Circuit {
+	return c.Append(Op{Name: "RY", Targets: []int{bit}, Params: []float64{theta}, Matrix: gate.RY(theta)})
+}
+
+// RZ : Appends a rotation of theta about the Z axis on the given qubit
+func (c *Circuit) RZ(bit int, theta float64) *Circuit {
+	return c.Append(Op{Name: "RZ", Targets: []int{bit}, Params: []float64{theta}, Matrix: gate.RZ(theta)})
+}
+
+// R : Appends the phase rotation R(k) on the given qubit
+func (c *Circuit) R(bit, k int) *Circuit {
+	return c.Append(Op{Name: "R", Targets: []int{bit}, Params: []float64{float64(k)}, Matrix: gate.R(k)})
+}
+
+// U : Appends the single bit gate U(alpha, beta, gamma, delta) on the given qubit
+func (c *Circuit) U(bit int, alpha, beta, gamma, delta float64) *Circuit {
+	return c.Append(Op{
+		Name:    "U",
+		Targets: []int{bit},
+		Params:  []float64{alpha, beta, gamma, delta},
+		Matrix:  gate.U(alpha, beta, gamma, delta),
+	})
+}
+
+// ControlledNot : Appends an X on t controlled by all bits in c
+func (c *Circuit) ControlledNot(control []int, t int) *Circuit {
+	return c.Append(Op{Name: "X", Controls: control, Targets: []int{t}, Matrix: gate.X()})
+}
+
+// CNOT : Appends an X on t controlled by c
+func (c *Circuit) CNOT(control, t int) *Circuit {
+	return c.ControlledNot([]int{control}, t)
+}
+
+// Toffoli : Appends an X on t controlled by c0 and c1
+func (c *Circuit) Toffoli(c0, c1, t int) *Circuit {
+	return c.ControlledNot([]int{c0, c1}, t)
+}
+
+// CZ : Appends a Z on t controlled by c
+func (c *Circuit) CZ(control, t int) *Circuit {
+	return c.Append(Op{Name: "Z", Controls: []int{control}, Targets: []int{t}, Matrix: gate.Z()})
+}
+
+// CS : Appends an S on t controlled by c
+func (c *Circuit) CS(control, t int) *Circuit {
+	return c.Append(Op{Name: "S", Controls: []int{control}, Targets: []int{t}, Matrix: gate.S()})
+}
+
+// CR : Appends R(k) on t controlled by c
+func (c *Circuit) CR(control, t, k int) *Circuit {
+	return c.Append(Op{
+		Name:     "R",
+		Controls: []int{control},
+		Targets:  []int{t},
+		Params:   []float64{float64(k)},
+		Matrix:   gate.R(k),
+	})
+}
+
+// Swap : Appends a swap of bits a and b
+func (c *Circuit) Swap(a, b int) *Circuit {
+	return c.Gate("Swap", gate.Swap(2, 0, 1), a, b)
+}
+
+// Fredkin : Appends a swap of bits a and b controlled by c
+func (c *Circuit) Fredkin(control, a, b int) *Circuit {
+	return c.Append(Op{Name: "Swap", Controls: []int{control}, Targets: []int{a, b}, Matrix: gate.Swap(2, 0, 1)})
+}
+
+// QFT : Appends the quantum fourier transform over the given bits
+func (c *Circuit) QFT(bit ...int) *Circuit {
+	return c.Gate("QFT", gate.QFT(len(bit)), bit...)
+}
+
+// Measure : Appends a measurement of the qubit in the computational basis into the classical bit
+func (c *Circuit) Measure(bit, clbit int) *Circuit {
+	return c.Append(Op{Name: "Measure", Targets: []int{bit}, Clbits: []int{clbit}})
+}
+
+// MeasureAll : Appends a measurement of every qubit into the classical bit of the same index
+func (c *Circuit) MeasureAll() *Circuit {
+	for i := 0; i < c.bit; i++ {
+		c.Measure(i, i)
+	}
+	return c
+}
+
+// Reset : Appends a reset of the qubit to |0>, a measurement with the result discarded followed
+// by an X when it was one
+func (c *Circuit) Reset(bit int) *Circuit {
+	return c.Append(Op{Name: "Reset", Targets: []int{bit}})
+}
+
+// If : Appends every operation of the body conditioned on the classical bits holding the value,
+// clbits[0] being the most significant bit. The body acts on the same qubits as the Circuit, and
+// Register.Bits gives the classical bits of a whole register
+func (c *Circuit) If(clbits []int, value int, body *Circuit) *Circuit {
+	if value < 0 || value >= 1<<uint(len(clbits)) {
+		panic(fmt.Sprintf("circuit: %d does not fit in %d classical bits", value, len(clbits)))
+	}
+	condition := &Condition{append([]int{}, clbits...), value}
+	for _, o := range body.ops {
+		if o.Condition != nil {
+			panic(fmt.Sprintf("circuit: %v is already conditioned", o))
+		}
+		// a barrier is not an operation to skip
+		if o.Name != "Barrier" {
+			o.Condition = condition
+		}
+		c.Append(o)
+	}
+	return c
+}
+
+// Barrier : Appends a barrier across the given qubits, or every qubit when none are given,
+// which passes do not optimise across
+func (c *Circuit) Barrier(bit ...int) *Circuit {
+	if len(bit) == 0 {
+		for i := 0; i < c.bit; i++ {
+			bit = append(bit, i)
+		}
+	}
+	return c.Append(Op{Name: "Barrier", Targets: bit})
+}
+
+// Controlled : Appends the gate on the given qubits, applied only when every control holds its
+// value (0 or 1). A nil value controls on one
+func (c *Circuit) Controlled(name string, input matrix.Matrix, control, value []int, qubits ...int) *Circuit {
+	c.open(control, value)
+	c.Append(Op{Name: name, Controls: append([]int{}, control...), Targets: qubits, Matrix: input})
+	return c.open(control, value)
+}
+
+// ControlledCircuit : Appends every operation of the input with the controls added, so the
+// input only runs when every control holds its value (0 or 1). Bit i of the input is placed on
+// qubits[i], or on bit i when no qubits are given. A nil value controls on one
+func (c *Circuit) ControlledCircuit(input *Circuit, control, value []int, qubits ...int) *Circuit {
+	if len(qubits) == 0 {
+		for i := 0; i < input.bit; i++ {
+			qubits = append(qubits, i)
+		}
+	}
+	if len(qubits) != input.bit {
+		panic(fmt.Sprintf("circuit: %d qubits given for a %d bit circuit", len(qubits), input.bit))
+	}
+	place := func(bit []int) (placed []int) {
+		for _, q := range bit {
+			placed = append(placed, qubits[q])
+		}
+		return
+	}
+	c.open(control, value)
+	for _, o := range input.ops {
+		o.Targets = place(o.Targets)
+		// operations without a matrix, such as barriers and measurements, are not controlled
+		if o.Matrix == nil {
+			o.Controls = place(o.Controls)
+			c.Append(o)
+			continue
+		}
+		o.Controls = append(append([]int{}, control...), place(o.Controls)...)
+		c.Append(o)
+	}
+	return c.open(control, value)
+}
+
+// open : Appends an X on every control whose value is zero, turning it into a control on one
+func (c *Circuit) open(control, value []int) *Circuit {
+	if value == nil {
+		return c
+	}
+	if len(value) != len(control) {
+		panic(fmt.Sprintf("circuit: %d values given for %d controls", len(value), len(control)))
+	}
+	for i, v := range value {
+		if v == 0 {
+			c.X(control[i])
+		}
+	}
+	return c
+}
+
+// Unitary : Returns the matrix of the whole Circuit, measurements, resets, barriers and
+// conditioned operations are skipped
+func (c *Circuit) Unitary() matrix.Matrix {
+	dim := 1 << uint(c.bit)
+	u := make(matrix.Matrix, dim)
+	for i := range u {
+		u[i] = make([]complex128, dim)
+	}
+	// each column is the Circuit run on a basis state
+	for j := 0; j < dim; j++ {
+		basis := make([]complex128, dim)
+		basis[j] = 1
+		for i, amplitude := range c.sweep(qubit.NewState(basis...)).Amplitude() {
+			u[i][j] = amplitude
+		}
+	}
+	return u
+}
+
+// Run : Returns a new Qubit holding the input after every operation of the Circuit,
+// each operation is a single sweep over the state. Measurements, resets, barriers and
+// conditioned operations are skipped, leaving the state as it was before measurement, see
+// Execute to run them
+func (c *Circuit) Run(input *qubit.Qubit) *qubit.Qubit {
+	return c.sweep(qubit.FromQubit[complex128](input)).Qubit()
+}
+
+// sweep : Applies each operation to the state in order
+func (c *Circuit) sweep(state *qubit.State[complex128]) *qubit.State[complex128] {
+	for _, o := range c.ops {
+		if o.Matrix == nil || o.Condition != nil {
+			continue
+		}
+		state.ApplyAt(o.Unitary(), o.Qubits()...)
+	}
+	return state
+}
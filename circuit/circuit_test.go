@@ -0,0 +1,76 @@
+package circuit
+
+import (
+	"testing"
+
+	"github.com/benluxford/qe/gate"
+)
+
+func TestControlled(t *testing.T) {
+	u := gate.U(0.3, 1.1, -0.7, 2.2)
+	tests := []struct {
+		control, value []int
+		qubits         []int
+	}{
+		{[]int{0}, nil, []int{2}},
+		{[]int{2}, []int{0}, []int{0}},
+		{[]int{0, 3}, []int{1, 0}, []int{1}},
+		{[]int{3, 1}, []int{0, 0}, []int{2}},
+	}
+	for _, tt := range tests {
+		got := New(4).Controlled("U", u, tt.control, tt.value, tt.qubits...).Unitary()
+		if want := gate.Controlled(4, u, tt.control, tt.value, tt.qubits...); !got.Equals(want, 1e-12) {
+			t.Errorf("controls %v on %v: the circuit is not gate.Controlled", tt.control, tt.value)
+		}
+	}
+	// the matrix of a controlled Op is gate.Controlled on its qubits
+	o := Op{Name: "U", Controls: []int{0, 1}, Targets: []int{2, 3}, Matrix: gate.Swap(2, 0, 1).Apply(gate.CS(2, 0, 1))}
+	if !o.Unitary().Equals(gate.Controlled(4, o.Matrix, []int{0, 1}, nil, 2, 3), 1e-12) {
+		t.Error("Op.Unitary is not gate.Controlled")
+	}
+}
+
+func TestControlledCircuit(t *testing.T) {
+	body := New(2).H(0).CNOT(0, 1).T(1).CS(1, 0)
+	tests := []struct {
+		name           string
+		control, value []int
+		qubits         []int
+	}{
+		{"on one", []int{0}, nil, []int{1, 2}},
+		{"on zero", []int{0}, []int{0}, []int{2, 1}},
+		{"mixed", []int{3, 0}, []int{1, 0}, []int{2, 1}},
+		{"in place", []int{2, 3}, []int{0, 1}, nil},
+	}
+	for _, tt := range tests {
+		qubits := tt.qubits
+		if qubits == nil {
+			qubits = []int{0, 1}
+		}
+		got := New(4).ControlledCircuit(body, tt.control, tt.value, tt.qubits...)
+		want := gate.Controlled(4, body.Unitary(), tt.control, tt.value, qubits...)
+		if !got.Unitary().Equals(want, 1e-12) {
+			t.Errorf("%s: the circuit is not the body controlled", tt.name)
+		}
+	}
+}
+
+func TestControlledPanics(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func()
+	}{
+		{"values", func() { New(3).Controlled("X", gate.X(), []int{0, 1}, []int{1}, 2) }},
+		{"qubits", func() { New(3).ControlledCircuit(New(2).H(0), []int{0}, nil, 1) }},
+	}
+	for _, tt := range tests {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected a panic", tt.name)
+				}
+			}()
+			tt.run()
+		}()
+	}
+}
@@ -0,0 +1,208 @@
+package circuit
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/benluxford/qe/gate"
+	"github.com/benluxford/qe/matrix"
+	"github.com/benluxford/qe/qubit"
+)
+
+// Op : A single operation in a Circuit, the gate Matrix acts on Targets when every Control is one.
+// Operations without a Matrix, such as measurements, resets and barriers, are not unitary and
+// write any result to the classical Clbits. An Op with a Condition only runs when the
+// classical bits hold its value
+type Op struct {
+	Name      string
+	Controls  []int
+	Targets   []int
+	Params    []float64
+	Matrix    matrix.Matrix
+	Clbits    []int
+	Condition *Condition
+}
+
+// Condition : A classical test on an Op, true when Clbits read as Value with Clbits[0] as the
+// most significant bit, as in OpenQASM if (c == k)
+type Condition struct {
+	Clbits []int
+	Value  int
+}
+
+// Holds : Returns true if the classical bits hold the value of the Condition
+func (c Condition) Holds(clbits []int) bool {
+	value := 0
+	for _, b := range c.Clbits {
+		value = value<<1 | clbits[b]
+	}
+	return value == c.Value
+}
+
+// String : Returns the Condition as e.g. c[0, 1] == 3
+func (c Condition) String() string {
+	clbits := []string{}
+	for _, b := range c.Clbits {
+		clbits = append(clbits, fmt.Sprint(b))
+	}
+	return fmt.Sprintf("c[%s] == %d", strings.Join(clbits, ", "), c.Value)
+}
+
+// Qubits : Returns the controls followed by the targets of the Op
+func (o Op) Qubits() (qubits []int) {
+	qubits = append(qubits, o.Controls...)
+	qubits = append(qubits, o.Targets...)
+	return
+}
+
+// Unitary : Returns the matrix of the Op on Qubits(), the Matrix with its controls applied
+func (o Op) Unitary() matrix.Matrix {
+	// without controls the gate is the matrix itself
+	if len(o.Controls) == 0 {
+		return o.Matrix
+	}
+	// controls are the most significant bits, so the gate fills the last block of an identity
+	dim := 1 << uint(len(o.Qubits()))
+	block := len(o.Matrix)
+	u := make(matrix.Matrix, dim)
+	for i := range u {
+		u[i] = make([]complex128, dim)
+		if i < dim-block {
+			u[i][i] = 1
+			continue
+		}
+		copy(u[i][dim-block:], o.Matrix[i-(dim-block)])
+	}
+	return u
+}
+
+// Acts : Returns true if the Op touches the given qubit
+func (o Op) Acts(bit int) bool {
+	for _, q := range o.Qubits() {
+		if q == bit {
+			return true
+		}
+	}
+	return false
+}
+
+// Standard : Returns true if the Matrix is the gate the Name stands for given the Params, e.g.
+// an H holding the Hadamard or an RZ holding gate.RZ(Params[0]), so passes that act on names
+// can trust them. An Op built by Gate from another matrix under a known name is not standard
+func (o Op) Standard() bool {
+	if o.Matrix == nil {
+		return false
+	}
+	var expected matrix.Matrix
+	params := map[string]int{
+		"I": 0, "X": 0, "Y": 0, "Z": 0, "H": 0, "S": 0, "Sdg": 0, "T": 0, "Tdg": 0, "SX": 0,
+		"P": 1, "RX": 1, "RY": 1, "RZ": 1, "R": 1, "U3": 3, "U": 4, "Swap": 0, "QFT": 0,
+	}
+	n, ok := params[o.Name]
+	if !ok || len(o.Params) != n {
+		return false
+	}
+	switch o.Name {
+	case "I":
+		expected = gate.I()
+	case "X":
+		expected = gate.X()
+	case "Y":
+		expected = gate.Y()
+	case "Z":
+		expected = gate.Z()
+	case "H":
+		expected = gate.H()
+	case "S":
+		expected = gate.S()
+	case "Sdg":
+		expected = gate.SDagger()
+	case "T":
+		expected = gate.T()
+	case "Tdg":
+		expected = gate.TDagger()
+	case "SX":
+		expected = gate.SX()
+	case "P":
+		expected = gate.P(o.Params[0])
+	case "RX":
+		expected = gate.RX(o.Params[0])
+	case "RY":
+		expected = gate.RY(o.Params[0])
+	case "RZ":
+		expected = gate.RZ(o.Params[0])
+	case "R":
+		expected = gate.R(int(o.Params[0]))
+	case "U3":
+		expected = gate.U3(o.Params[0], o.Params[1], o.Params[2])
+	case "U":
+		expected = gate.U(o.Params[0], o.Params[1], o.Params[2], o.Params[3])
+	case "Swap":
+		expected = gate.Swap(2, 0, 1)
+	case "QFT":
+		expected = gate.QFT(len(o.Targets))
+	}
+	if rows, _ := expected.Dimension(); rows != 1<<uint(len(o.Targets)) {
+		return false
+	}
+	return o.Matrix.Equals(expected, standardEps)
+}
+
+// standardEps : How far a Matrix may stray from the gate its Name stands for
+const standardEps = 1e-9
+
+// Label : Returns the name of the Op with a C for each control, e.g. CX or CCX
+func (o Op) Label() string {
+	return strings.Repeat("C", len(o.Controls)) + o.Name
+}
+
+// String : Returns the Op as e.g. H(0), CX(0, 1), R[3](2), Measure(1) -> 0 or X(2) if c[0] == 1
+func (o Op) String() string {
+	name := o.Label()
+	if len(o.Params) > 0 {
+		params := []string{}
+		for _, p := range o.Params {
+			params = append(params, fmt.Sprintf("%.4g", p))
+		}
+		name += "[" + strings.Join(params, ", ") + "]"
+	}
+	qubits := []string{}
+	for _, q := range o.Qubits() {
+		qubits = append(qubits, fmt.Sprint(q))
+	}
+	name += "(" + strings.Join(qubits, ", ") + ")"
+	if len(o.Clbits) > 0 {
+		clbits := []string{}
+		for _, b := range o.Clbits {
+			clbits = append(clbits, fmt.Sprint(b))
+		}
+		name += " -> " + strings.Join(clbits, ", ")
+	}
+	if o.Condition != nil {
+		name += " if " + o.Condition.String()
+	}
+	return name
+}
+
+// Expand : Returns the gate on len(qubits) bits expanded to a bit wide matrix, acting on
+// the given qubits. qubits[0] is the most significant bit of the gate
+func Expand(input matrix.Matrix, bit int, qubits ...int) matrix.Matrix {
+	dim := 1 << uint(bit)
+	expanded := make(matrix.Matrix, dim)
+	for i := range expanded {
+		expanded[i] = make([]complex128, dim)
+	}
+	column := make([]complex128, dim)
+	// each column of the expanded gate is the gate applied to a basis state
+	for j := 0; j < dim; j++ {
+		for i := range column {
+			column[i] = 0
+		}
+		column[j] = 1
+		applied := qubit.NewState(column...).ApplyAt(input, qubits...).Amplitude()
+		for i := range applied {
+			expanded[i][j] = applied[i]
+		}
+	}
+	return expanded
+}
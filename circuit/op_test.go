@@ -0,0 +1,34 @@
+package circuit
+
+import (
+	"testing"
+
+	"github.com/benluxford/qe/gate"
+)
+
+func TestStandard(t *testing.T) {
+	tests := []struct {
+		name string
+		op   Op
+		want bool
+	}{
+		{"H", New(1).H(0).Ops()[0], true},
+		{"CX", New(2).CNOT(0, 1).Ops()[0], true},
+		{"RZ", New(1).RZ(0, 0.3).Ops()[0], true},
+		{"U", New(1).U(0, 0.1, 0.2, 0.3, 0.4).Ops()[0], true},
+		{"CR", New(2).CR(0, 1, 3).Ops()[0], true},
+		{"Swap", New(2).Swap(0, 1).Ops()[0], true},
+		{"QFT", New(3).QFT(0, 1, 2).Ops()[0], true},
+		{"H holding S", New(1).Gate("H", gate.S(), 0).Ops()[0], false},
+		{"RZ without params", New(1).Gate("RZ", gate.RZ(0.3), 0).Ops()[0], false},
+		{"RZ with the wrong angle", Op{Name: "RZ", Targets: []int{0}, Params: []float64{0.2}, Matrix: gate.RZ(0.3)}, false},
+		{"X on two targets", Op{Name: "X", Targets: []int{0, 1}, Matrix: gate.X()}, false},
+		{"unknown name", New(2).Gate("Fused", gate.CNOT(2, 0, 1), 0, 1).Ops()[0], false},
+		{"Measure", New(1).Measure(0, 0).Ops()[0], false},
+	}
+	for _, tt := range tests {
+		if got := tt.op.Standard(); got != tt.want {
+			t.Errorf("%s: Standard() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,318 @@
+package circuit
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/benluxford/qe/qubit"
+)
+
+// Register : A named, contiguous run of Size bits starting at Start, either quantum bits
+// (OpenQASM qreg) or classical bits (OpenQASM creg) of a Circuit
+type Register struct {
+	Name  string
+	Start int
+	Size  int
+}
+
+// At : Returns the Circuit index of bit i of the Register, panics if i is out of range
+func (r Register) At(i int) int {
+	if i < 0 || i >= r.Size {
+		panic(fmt.Sprintf("circuit: %s[%d] is outside of %d bits", r.Name, i, r.Size))
+	}
+	return r.Start + i
+}
+
+// Bits : Returns the Circuit indices of every bit of the Register in order
+func (r Register) Bits() (bits []int) {
+	for i := 0; i < r.Size; i++ {
+		bits = append(bits, r.Start+i)
+	}
+	return
+}
+
+// String : Returns the Register as e.g. data[3]
+func (r Register) String() string {
+	return fmt.Sprintf("%s[%d]", r.Name, r.Size)
+}
+
+// QuantumRegister : Returns a new Register of size qubits appended after the existing qubits of
+// the Circuit, panics if the name is already used
+func (c *Circuit) QuantumRegister(name string, size int) Register {
+	c.unique(name)
+	r := Register{name, c.bit, size}
+	c.bit += size
+	c.qregs = append(c.qregs, r)
+	return r
+}
+
+// ClassicalRegister : Returns a new Register of size classical bits appended after the existing
+// classical bits of the Circuit, panics if the name is already used
+func (c *Circuit) ClassicalRegister(name string, size int) Register {
+	c.unique(name)
+	r := Register{name, c.clbit, size}
+	c.clbit += size
+	c.cregs = append(c.cregs, r)
+	return r
+}
+
+// QuantumRegisters : Returns the quantum registers of the Circuit in order
+func (c *Circuit) QuantumRegisters() []Register {
+	return c.qregs
+}
+
+// ClassicalRegisters : Returns the classical registers of the Circuit in order
+func (c *Circuit) ClassicalRegisters() []Register {
+	return c.cregs
+}
+
+// QubitName : Returns the name of a qubit, e.g. data[1], or q[i] when it is in no register
+func (c *Circuit) QubitName(bit int) string {
+	for _, r := range c.qregs {
+		if bit >= r.Start && bit < r.Start+r.Size {
+			return fmt.Sprintf("%s[%d]", r.Name, bit-r.Start)
+		}
+	}
+	return fmt.Sprintf("q[%d]", bit)
+}
+
+// MeasureRegister : Appends a measurement of every qubit of q into the classical bit of c with
+// the same position, panics if the registers differ in size
+func (c *Circuit) MeasureRegister(q, clbits Register) *Circuit {
+	if q.Size != clbits.Size {
+		panic(fmt.Sprintf("circuit: cannot measure %v into %v", q, clbits))
+	}
+	for i := 0; i < q.Size; i++ {
+		c.Measure(q.At(i), clbits.At(i))
+	}
+	return c
+}
+
+// ComposeOn : Returns the Circuit with every operation of the input appended, bit i of the
+// input placed on qubits[i] and classical bit j on clbits[j]. Register.Bits gives the indices
+// of a whole register, and nil clbits keeps the input's classical bits as they are
+func (c *Circuit) ComposeOn(input *Circuit, qubits, clbits []int) *Circuit {
+	if len(qubits) != input.bit {
+		panic(fmt.Sprintf("circuit: %d qubits given for a %d bit circuit", len(qubits), input.bit))
+	}
+	if clbits != nil && len(clbits) != input.clbit {
+		panic(fmt.Sprintf("circuit: %d classical bits given for a circuit with %d", len(clbits), input.clbit))
+	}
+	place := func(bit, to []int) (placed []int) {
+		if to == nil {
+			return append(placed, bit...)
+		}
+		for _, b := range bit {
+			placed = append(placed, to[b])
+		}
+		return
+	}
+	for _, o := range input.ops {
+		o.Controls = place(o.Controls, qubits)
+		o.Targets = place(o.Targets, qubits)
+		o.Clbits = place(o.Clbits, clbits)
+		// the body of an If shares one Condition, so each op gets its own placed copy
+		if o.Condition != nil {
+			o.Condition = &Condition{place(o.Condition.Clbits, clbits), o.Condition.Value}
+		}
+		c.Append(o)
+	}
+	return c
+}
+
+// unique : Panics if a register of either kind already has the name
+func (c *Circuit) unique(name string) {
+	for _, r := range append(append([]Register{}, c.qregs...), c.cregs...) {
+		if r.Name == name {
+			panic(fmt.Sprintf("circuit: register %q already exists", name))
+		}
+	}
+}
+
+// Result : The classical bits written by each shot of an executed Circuit, tallied as bit
+// strings with classical bit 0 on the left
+type Result struct {
+	Shots     int
+	counts    map[string]int
+	registers []Register
+}
+
+// Counts : Returns the number of shots giving each string of every classical bit
+func (r Result) Counts() map[string]int {
+	return r.counts
+}
+
+// Register : Returns the number of shots giving each value of the named classical register,
+// bit 0 of the register on the left, or an error if there is no such register
+func (r Result) Register(name string) (map[string]int, error) {
+	for _, reg := range r.registers {
+		if reg.Name != name {
+			continue
+		}
+		counts := map[string]int{}
+		for bits, n := range r.counts {
+			counts[bits[reg.Start:reg.Start+reg.Size]] += n
+		}
+		return counts, nil
+	}
+	return nil, fmt.Errorf("circuit: no classical register %q", name)
+}
+
+// String : Returns the counts in order of their bit strings, split into registers, e.g.
+// "01 1: 512" for a two bit and a one bit register
+func (r Result) String() string {
+	keys := []string{}
+	for k := range r.counts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	lines := []string{}
+	for _, k := range keys {
+		lines = append(lines, fmt.Sprintf("%s: %d", r.split(k), r.counts[k]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// split : Returns the bit string with a space between registers
+func (r Result) split(bits string) string {
+	if len(r.registers) == 0 {
+		return bits
+	}
+	parts := []string{}
+	at := 0
+	for _, reg := range r.registers {
+		if reg.Start > at {
+			parts = append(parts, bits[at:reg.Start])
+		}
+		parts = append(parts, bits[reg.Start:reg.Start+reg.Size])
+		at = reg.Start + reg.Size
+	}
+	if at < len(bits) {
+		parts = append(parts, bits[at:])
+	}
+	return strings.Join(parts, " ")
+}
+
+// Execute : Returns the Result of running the Circuit shots times on the input, or on all zeros
+// when the input is nil. When every measurement comes at the end of its qubit the final state
+// is computed once and each shot samples every qubit at once from it, a classical bit measured
+// more than once holding the last measurement. Circuits with mid-circuit measurements, resets
+// or conditioned operations are simulated shot by shot, collapsing the state at each
+// measurement. A nil rng is seeded from the time
+func (c *Circuit) Execute(input *qubit.Qubit, shots int, rng *rand.Rand) Result {
+	if input == nil {
+		input = qubit.Zero(c.bit)
+	}
+	if rng == nil {
+		rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	result := Result{Shots: shots, counts: map[string]int{}, registers: c.cregs}
+	if c.dynamic() {
+		start := qubit.FromQubit[complex128](input)
+		for s := 0; s < shots; s++ {
+			result.counts[bitString(c.shot(start.Clone(), rng))]++
+		}
+		return result
+	}
+	// the qubit each classical bit finally reads, or -1 if it is never written
+	reads := make([]int, c.clbit)
+	for i := range reads {
+		reads[i] = -1
+	}
+	for _, o := range c.ops {
+		if o.Name == "Measure" {
+			for i, b := range o.Clbits {
+				reads[b] = o.Targets[i]
+			}
+		}
+	}
+	probability := c.Run(input).Probability()
+	clbits := make([]int, c.clbit)
+	for s := 0; s < shots; s++ {
+		index := sample(probability, rng.Float64())
+		for b, q := range reads {
+			clbits[b] = 0
+			if q >= 0 {
+				clbits[b] = qubit.BigEndian.Bit(index, q, c.bit)
+			}
+		}
+		result.counts[bitString(clbits)]++
+	}
+	return result
+}
+
+// dynamic : Returns true if the Circuit resets, conditions an operation or acts on a qubit
+// after measuring it, so it can not be sampled from a single final state
+func (c *Circuit) dynamic() bool {
+	measured := make([]bool, c.bit)
+	for _, o := range c.ops {
+		if o.Name == "Reset" || o.Condition != nil {
+			return true
+		}
+		for _, q := range o.Qubits() {
+			if measured[q] && o.Name != "Measure" && o.Name != "Barrier" {
+				return true
+			}
+		}
+		if o.Name == "Measure" {
+			for _, q := range o.Targets {
+				measured[q] = true
+			}
+		}
+	}
+	return false
+}
+
+// shot : Returns the classical bits after running every operation on the state once,
+// measurements and resets collapsing it with values drawn from the rng
+func (c *Circuit) shot(state *qubit.State[complex128], rng *rand.Rand) []int {
+	clbits := make([]int, c.clbit)
+	for _, o := range c.ops {
+		if o.Condition != nil && !o.Condition.Holds(clbits) {
+			continue
+		}
+		switch {
+		case o.Name == "Measure":
+			for i, q := range o.Targets {
+				clbits[o.Clbits[i]] = state.MeasureAt(q, rng.Float64())
+			}
+		case o.Name == "Reset":
+			for _, q := range o.Targets {
+				state.ResetAt(q, rng.Float64())
+			}
+		case o.Matrix != nil:
+			state.ApplyAt(o.Unitary(), o.Qubits()...)
+		}
+	}
+	return clbits
+}
+
+// bitString : Returns the classical bits as a string, bit 0 on the left
+func bitString(clbits []int) string {
+	bits := make([]byte, len(clbits))
+	for i, b := range clbits {
+		bits[i] = byte('0' + b)
+	}
+	return string(bits)
+}
+
+// sample : Returns the index the random value in [0, 1) falls on in the cumulative probabilities
+func sample(probability []float64, value float64) int {
+	sum := 0.0
+	for i, p := range probability {
+		sum += p
+		if value < sum {
+			return i
+		}
+	}
+	// rounding can leave the sum just short of one
+	for i := len(probability) - 1; i > 0; i-- {
+		if probability[i] > 0 {
+			return i
+		}
+	}
+	return 0
+}
@@ -0,0 +1,175 @@
+package circuit
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/benluxford/qe/qubit"
+)
+
+func TestRegisters(t *testing.T) {
+	c := New(1)
+	data := c.QuantumRegister("data", 3)
+	ancilla := c.QuantumRegister("ancilla", 1)
+	low := c.ClassicalRegister("low", 2)
+	high := c.ClassicalRegister("high", 1)
+	if data != (Register{"data", 1, 3}) || ancilla != (Register{"ancilla", 4, 1}) {
+		t.Errorf("got quantum registers %+v %+v", data, ancilla)
+	}
+	if low != (Register{"low", 0, 2}) || high != (Register{"high", 2, 1}) {
+		t.Errorf("got classical registers %+v %+v", low, high)
+	}
+	if c.NumberOfBit() != 5 || c.NumberOfClbit() != 3 {
+		t.Errorf("got %d qubits and %d classical bits, want 5 and 3", c.NumberOfBit(), c.NumberOfClbit())
+	}
+	if got := c.QuantumRegisters(); len(got) != 2 || got[0] != data || got[1] != ancilla {
+		t.Errorf("got quantum registers %v", got)
+	}
+	if got := c.ClassicalRegisters(); len(got) != 2 || got[0] != low || got[1] != high {
+		t.Errorf("got classical registers %v", got)
+	}
+	if data.At(2) != 3 || data.String() != "data[3]" {
+		t.Errorf("got %d and %s", data.At(2), data)
+	}
+	if got := data.Bits(); len(got) != 3 || got[0] != 1 || got[2] != 3 {
+		t.Errorf("got bits %v, want [1 2 3]", got)
+	}
+	names := []string{"q[0]", "data[0]", "data[1]", "data[2]", "ancilla[0]", "q[5]"}
+	for bit, want := range names {
+		if got := c.QubitName(bit); got != want {
+			t.Errorf("qubit %d is %s, want %s", bit, got, want)
+		}
+	}
+}
+
+func TestRegistersPanic(t *testing.T) {
+	tests := []struct {
+		name string
+		f    func()
+	}{
+		{"same quantum name", func() { c := New(0); c.QuantumRegister("a", 1); c.QuantumRegister("a", 2) }},
+		{"same name of either kind", func() { c := New(0); c.QuantumRegister("a", 1); c.ClassicalRegister("a", 1) }},
+		{"outside the register", func() { New(0).QuantumRegister("a", 2).At(2) }},
+		{"negative index", func() { New(0).QuantumRegister("a", 2).At(-1) }},
+		{"measure sizes", func() {
+			c := New(0)
+			c.MeasureRegister(c.QuantumRegister("q", 2), c.ClassicalRegister("c", 1))
+		}},
+		{"compose qubits", func() { New(3).ComposeOn(New(2), []int{0}, nil) }},
+		{"compose classical bits", func() { New(3).ComposeOn(New(1).Measure(0, 1), []int{0}, []int{0}) }},
+	}
+	for _, tt := range tests {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: did not panic", tt.name)
+				}
+			}()
+			tt.f()
+		}()
+	}
+}
+
+func TestComposeOn(t *testing.T) {
+	sub := New(2).X(0).CNOT(0, 1).Measure(0, 0).Measure(1, 1)
+	c := New(4)
+	c.ComposeOn(sub, []int{3, 1}, []int{2, 0})
+	want := [][3][]int{
+		{nil, {3}, nil},
+		{{3}, {1}, nil},
+		{nil, {3}, {2}},
+		{nil, {1}, {0}},
+	}
+	for i, o := range c.Ops() {
+		got := [3][]int{o.Controls, o.Targets, o.Clbits}
+		for k := range got {
+			if len(got[k]) != len(want[i][k]) || (len(got[k]) > 0 && got[k][0] != want[i][k][0]) {
+				t.Errorf("op %d: got controls, targets and clbits %v, want %v", i, got, want[i])
+				break
+			}
+		}
+	}
+	if c.NumberOfClbit() != 3 {
+		t.Errorf("got %d classical bits, want 3", c.NumberOfClbit())
+	}
+	// qubits 1 and 3 are set, |0101>
+	if got := c.Run(qubit.Zero(4)); got.Probability()[5] < 1-1e-9 {
+		t.Errorf("got %v, want |0101>", got.Probability())
+	}
+	// the input is left as it was
+	if o := sub.Ops()[1]; o.Controls[0] != 0 || o.Targets[0] != 1 {
+		t.Errorf("input changed to %v", o)
+	}
+	// nil classical bits are kept as they are
+	kept := New(3).ComposeOn(sub, []int{0, 2}, nil)
+	if o := kept.Ops()[3]; o.Targets[0] != 2 || o.Clbits[0] != 1 {
+		t.Errorf("got %v, want a measurement of 2 into 1", o)
+	}
+}
+
+func TestResultRegister(t *testing.T) {
+	c := New(0)
+	data := c.QuantumRegister("data", 3)
+	a := c.ClassicalRegister("a", 1)
+	b := c.ClassicalRegister("b", 2)
+	c.X(data.At(0)).X(data.At(2))
+	c.Measure(data.At(0), a.At(0)).Measure(data.At(1), b.At(0)).Measure(data.At(2), b.At(1))
+	// a classical bit in no register
+	c.Measure(data.At(0), 3)
+	result := c.Execute(nil, 20, rand.New(rand.NewSource(1)))
+	if got := result.Counts(); got["1011"] != 20 || len(got) != 1 {
+		t.Errorf("got counts %v, want 1011 every shot", got)
+	}
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"a", "1"},
+		{"b", "01"},
+	}
+	for _, tt := range tests {
+		counts, err := result.Register(tt.name)
+		if err != nil || counts[tt.want] != 20 || len(counts) != 1 {
+			t.Errorf("%s: got %v %v, want %s every shot", tt.name, counts, err, tt.want)
+		}
+	}
+	if _, err := result.Register("data"); err == nil {
+		t.Errorf("a quantum register gave no error")
+	}
+	if got := result.String(); got != "1 01 1: 20" {
+		t.Errorf("got %q, want %q", got, "1 01 1: 20")
+	}
+
+	// a register measured in superposition splits its shots
+	c = New(0)
+	q := c.QuantumRegister("q", 2)
+	r := c.ClassicalRegister("r", 2)
+	c.H(q.At(0)).CNOT(q.At(0), q.At(1)).MeasureRegister(q, r)
+	counts, err := c.Execute(nil, 200, rand.New(rand.NewSource(2))).Register("r")
+	if err != nil || counts["00"]+counts["11"] != 200 || counts["00"] == 0 || counts["11"] == 0 {
+		t.Errorf("got %v %v, want only 00 and 11", counts, err)
+	}
+}
+
+func TestComposeOnPlacesConditions(t *testing.T) {
+	// measure a one, and flip the second qubit only if the measurement read one
+	sub := New(2).X(0).Measure(0, 0)
+	sub.If([]int{0}, 1, New(2).X(1)).Measure(1, 1)
+
+	c := New(2)
+	c.ClassicalRegister("low", 2)
+	c.ClassicalRegister("high", 2)
+	c.ComposeOn(sub, []int{0, 1}, c.ClassicalRegisters()[1].Bits())
+	result := c.Execute(nil, 10, rand.New(rand.NewSource(1)))
+	if got := result.Counts()["0011"]; got != 10 {
+		t.Errorf("got %v, want 0011 every shot", result.Counts())
+	}
+	high, err := result.Register("high")
+	if err != nil || high["11"] != 10 {
+		t.Errorf("got %v %v, want 11 in high every shot", high, err)
+	}
+	// the input keeps its own condition
+	if condition := sub.Ops()[2].Condition; condition.Clbits[0] != 0 {
+		t.Errorf("input condition moved to %v", condition)
+	}
+}
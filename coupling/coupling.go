@@ -0,0 +1,179 @@
+package coupling
+
+import "fmt"
+
+// Map : The connectivity of a device, an undirected graph of which bits can share a two bit gate
+type Map struct {
+	bit       int
+	neighbour [][]int
+	distance  [][]int
+}
+
+// FromEdges : Returns a pointer to a new Map on the given number of bits with the given edges
+func FromEdges(bit int, edges [][2]int) (m *Map, err error) {
+	m = &Map{bit: bit, neighbour: make([][]int, bit)}
+	for _, e := range edges {
+		a, b := e[0], e[1]
+		if a < 0 || b < 0 || a >= bit || b >= bit || a == b {
+			return nil, fmt.Errorf("coupling: invalid edge %v on %d bits", e, bit)
+		}
+		// ignore repeated edges
+		if m.Connected(a, b) {
+			continue
+		}
+		m.neighbour[a] = append(m.neighbour[a], b)
+		m.neighbour[b] = append(m.neighbour[b], a)
+	}
+	m.distances()
+	return
+}
+
+// Line : Returns a Map of bits connected in a line, 0 - 1 - ... - n-1
+func Line(bit int) *Map {
+	edges := [][2]int{}
+	for i := 0; i+1 < bit; i++ {
+		edges = append(edges, [2]int{i, i + 1})
+	}
+	return build(bit, edges)
+}
+
+// Ring : Returns a Map of bits connected in a line with the ends joined
+func Ring(bit int) *Map {
+	edges := [][2]int{}
+	for i := 0; i < bit; i++ {
+		edges = append(edges, [2]int{i, (i + 1) % bit})
+	}
+	return build(bit, edges)
+}
+
+// Grid : Returns a Map of rows x columns bits, each connected to its horizontal and vertical
+// neighbours, bit r*columns+c is at row r column c
+func Grid(rows, columns int) *Map {
+	edges := [][2]int{}
+	for r := 0; r < rows; r++ {
+		for c := 0; c < columns; c++ {
+			if c+1 < columns {
+				edges = append(edges, [2]int{r*columns + c, r*columns + c + 1})
+			}
+			if r+1 < rows {
+				edges = append(edges, [2]int{r*columns + c, (r+1)*columns + c})
+			}
+		}
+	}
+	return build(rows*columns, edges)
+}
+
+// HeavyHex : Returns a heavy hexagon Map, a honeycomb of rows x columns vertices laid out as
+// a brick wall with an extra bit on every edge. Vertices are bits 0 to rows*columns-1 and the
+// edge bits follow, so no bit has more than three neighbours
+func HeavyHex(rows, columns int) *Map {
+	// the honeycomb: every row is a path, rungs alternate to form hexagons
+	honeycomb := [][2]int{}
+	for r := 0; r < rows; r++ {
+		for c := 0; c < columns; c++ {
+			if c+1 < columns {
+				honeycomb = append(honeycomb, [2]int{r*columns + c, r*columns + c + 1})
+			}
+			if r+1 < rows && (r+c)%2 == 0 {
+				honeycomb = append(honeycomb, [2]int{r*columns + c, (r+1)*columns + c})
+			}
+		}
+	}
+	// split every edge with a new bit
+	bit := rows * columns
+	edges := [][2]int{}
+	for _, e := range honeycomb {
+		edges = append(edges, [2]int{e[0], bit}, [2]int{bit, e[1]})
+		bit++
+	}
+	return build(bit, edges)
+}
+
+// build : Returns the Map of edges that are known to be valid
+func build(bit int, edges [][2]int) *Map {
+	m, err := FromEdges(bit, edges)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// NumberOfBit : Returns the number of bits on the device
+func (m *Map) NumberOfBit() int {
+	return m.bit
+}
+
+// Edges : Returns every edge once, with the smaller bit first
+func (m *Map) Edges() (edges [][2]int) {
+	for a, neighbours := range m.neighbour {
+		for _, b := range neighbours {
+			if a < b {
+				edges = append(edges, [2]int{a, b})
+			}
+		}
+	}
+	return
+}
+
+// Neighbours : Returns the bits connected to the given bit
+func (m *Map) Neighbours(bit int) []int {
+	return m.neighbour[bit]
+}
+
+// Connected : Returns true if the two bits share an edge
+func (m *Map) Connected(a, b int) bool {
+	for _, n := range m.neighbour[a] {
+		if n == b {
+			return true
+		}
+	}
+	return false
+}
+
+// Distance : Returns the number of edges on the shortest path between two bits, -1 if there is none
+func (m *Map) Distance(a, b int) int {
+	return m.distance[a][b]
+}
+
+// Path : Returns the bits on a shortest path from a to b, including both, nil if there is none
+func (m *Map) Path(a, b int) (path []int) {
+	if m.distance[a][b] < 0 {
+		return nil
+	}
+	// walk towards b, always stepping to a neighbour one edge closer
+	path = []int{a}
+	for a != b {
+		for _, n := range m.neighbour[a] {
+			if m.distance[n][b] == m.distance[a][b]-1 {
+				a = n
+				break
+			}
+		}
+		path = append(path, a)
+	}
+	return
+}
+
+// distances : Fills the all pairs shortest path lengths with a breadth first search per bit
+func (m *Map) distances() {
+	m.distance = make([][]int, m.bit)
+	for source := range m.distance {
+		d := make([]int, m.bit)
+		for i := range d {
+			d[i] = -1
+		}
+		d[source] = 0
+		queue := []int{source}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			for _, n := range m.neighbour[current] {
+				if d[n] < 0 {
+					d[n] = d[current] + 1
+					queue = append(queue, n)
+				}
+			}
+		}
+		m.distance[source] = d
+	}
+}
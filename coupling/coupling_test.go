@@ -0,0 +1,112 @@
+package coupling
+
+import "testing"
+
+// degrees : Returns how many bits have each number of neighbours
+func degrees(m *Map) map[int]int {
+	count := map[int]int{}
+	for i := 0; i < m.NumberOfBit(); i++ {
+		count[len(m.Neighbours(i))]++
+	}
+	return count
+}
+
+func TestMaps(t *testing.T) {
+	tests := []struct {
+		name    string
+		m       *Map
+		bit     int
+		edges   int
+		degrees map[int]int
+	}{
+		{"line", Line(5), 5, 4, map[int]int{1: 2, 2: 3}},
+		{"single bit line", Line(1), 1, 0, map[int]int{0: 1}},
+		{"ring", Ring(6), 6, 6, map[int]int{2: 6}},
+		{"grid", Grid(3, 4), 12, 17, map[int]int{2: 4, 3: 6, 4: 2}},
+		{"grid row", Grid(1, 3), 3, 2, map[int]int{1: 2, 2: 1}},
+		// a 2x3 honeycomb is a single hexagon, its 6 edges each split by a bit of degree two
+		{"heavy hex", HeavyHex(2, 3), 12, 12, map[int]int{2: 12}},
+		// 9 row edges and 4 rungs, the rungs give vertices 2, 5, 6 and 9 a third neighbour
+		{"heavy hex 3x4", HeavyHex(3, 4), 12 + 13, 26, map[int]int{1: 2, 2: 19, 3: 4}},
+	}
+	for _, tt := range tests {
+		if got := tt.m.NumberOfBit(); got != tt.bit {
+			t.Errorf("%s: got %d bits, want %d", tt.name, got, tt.bit)
+		}
+		edges := tt.m.Edges()
+		if len(edges) != tt.edges {
+			t.Errorf("%s: got %d edges, want %d", tt.name, len(edges), tt.edges)
+		}
+		for _, e := range edges {
+			if e[0] >= e[1] || !tt.m.Connected(e[0], e[1]) || !tt.m.Connected(e[1], e[0]) {
+				t.Errorf("%s: edge %v", tt.name, e)
+			}
+		}
+		got := degrees(tt.m)
+		if len(got) != len(tt.degrees) {
+			t.Errorf("%s: got degrees %v, want %v", tt.name, got, tt.degrees)
+		}
+		for d, n := range tt.degrees {
+			if got[d] != n {
+				t.Errorf("%s: got degrees %v, want %v", tt.name, got, tt.degrees)
+				break
+			}
+		}
+		// every map is connected
+		for i := 0; i < tt.m.NumberOfBit(); i++ {
+			if tt.m.Distance(0, i) < 0 {
+				t.Errorf("%s: bit %d is not connected to bit 0", tt.name, i)
+			}
+		}
+	}
+}
+
+func TestDistanceAndPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		m        *Map
+		a, b     int
+		distance int
+	}{
+		{"line", Line(6), 1, 5, 4},
+		{"ring", Ring(6), 0, 5, 1},
+		{"ring across", Ring(6), 0, 3, 3},
+		{"grid", Grid(3, 4), 0, 11, 5},
+		{"same bit", Grid(3, 4), 6, 6, 0},
+		// vertices 0 and 1 are joined through edge bit 6
+		{"heavy hex", HeavyHex(2, 3), 0, 1, 2},
+	}
+	for _, tt := range tests {
+		if got := tt.m.Distance(tt.a, tt.b); got != tt.distance {
+			t.Errorf("%s: got distance %d, want %d", tt.name, got, tt.distance)
+		}
+		path := tt.m.Path(tt.a, tt.b)
+		if len(path) != tt.distance+1 || path[0] != tt.a || path[len(path)-1] != tt.b {
+			t.Errorf("%s: got path %v", tt.name, path)
+			continue
+		}
+		for i := 1; i < len(path); i++ {
+			if !tt.m.Connected(path[i-1], path[i]) {
+				t.Errorf("%s: path %v steps off the map", tt.name, path)
+			}
+		}
+	}
+}
+
+func TestFromEdges(t *testing.T) {
+	m, err := FromEdges(4, [][2]int{{0, 1}, {1, 2}, {2, 1}, {0, 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Edges()) != 2 || len(m.Neighbours(1)) != 2 {
+		t.Errorf("repeated edges were kept, got %v", m.Edges())
+	}
+	if m.Distance(0, 3) != -1 || m.Path(0, 3) != nil {
+		t.Errorf("bit 3 has no edges, got distance %d", m.Distance(0, 3))
+	}
+	for _, edges := range [][][2]int{{{0, 4}}, {{-1, 0}}, {{2, 2}}, {{0, 1}, {1, 5}}} {
+		if _, err := FromEdges(4, edges); err == nil {
+			t.Errorf("%v: expected an error", edges)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package decompose
+
+import (
+	"fmt"
+	"math/cmplx"
+
+	"github.com/benluxford/qe/circuit"
+	"github.com/benluxford/qe/gate"
+	"github.com/benluxford/qe/matrix"
+)
+
+// Strategy : How spare bits may be used when decomposing multi controlled gates
+type Strategy int
+
+const (
+	// NoAncilla : No spare bits, the cost grows exponentially with the number of controls
+	NoAncilla Strategy = iota
+	// CleanAncilla : Spare bits that start in zero and are returned to zero
+	CleanAncilla
+	// DirtyAncilla : Spare bits in an unknown state that are returned to that state
+	DirtyAncilla
+)
+
+// Ancillas : The spare bits a decomposition may use and how
+type Ancillas struct {
+	Strategy Strategy
+	Bits     []int
+}
+
+// Cost : The size of a Circuit
+type Cost struct {
+	Gates, TwoBit, Toffoli, Depth int
+}
+
+// String : Returns the Cost as e.g. "12 gates (6 two bit, 0 Toffoli), depth 9"
+func (c Cost) String() string {
+	return fmt.Sprintf("%d gates (%d two bit, %d Toffoli), depth %d", c.Gates, c.TwoBit, c.Toffoli, c.Depth)
+}
+
+// CostOf : Returns the number of gates, two bit gates, Toffolis and the depth of a Circuit
+func CostOf(c *circuit.Circuit) (cost Cost) {
+	for _, o := range c.Ops() {
+		cost.Gates++
+		switch len(o.Qubits()) {
+		case 2:
+			cost.TwoBit++
+		case 3:
+			if o.Name == "X" && len(o.Controls) == 2 {
+				cost.Toffoli++
+			}
+		}
+	}
+	cost.Depth = c.Depth()
+	return
+}
+
+// MultiControlled : Returns a Circuit on bit bits applying the 2x2 unitary u, called name, to t
+// when every control is one. The result uses Toffolis, singly controlled and single bit gates;
+// with enough ancillas the number of Toffolis is linear in the number of controls (clean
+// bits need len(controls)-1 for arbitrary u and len(controls)-2 for X, dirty bits need
+// len(controls)-2), otherwise square roots of u are chained at exponential cost
+func MultiControlled(name string, u matrix.Matrix, bit int, controls []int, t int, ancillas Ancillas) (*circuit.Circuit, error) {
+	if err := single(u); err != nil {
+		return nil, err
+	}
+	out := circuit.New(bit)
+	multiControlled(out, name, u, controls, t, ancillas)
+	return out, nil
+}
+
+// ExpandToffoli : Returns the Circuit with every Toffoli replaced by CX and single bit gates,
+// an X with two controls only counts as a Toffoli when its matrix is X
+func ExpandToffoli(c *circuit.Circuit) *circuit.Circuit {
+	expanded := c.Empty()
+	for _, o := range c.Ops() {
+		if o.Name != "X" || len(o.Controls) != 2 || !o.Standard() {
+			expanded.Append(o)
+			continue
+		}
+		if o.Condition != nil {
+			body := c.Empty()
+			Toffoli(body, o.Controls[0], o.Controls[1], o.Targets[0])
+			expanded.If(o.Condition.Clbits, o.Condition.Value, body)
+			continue
+		}
+		Toffoli(expanded, o.Controls[0], o.Controls[1], o.Targets[0])
+	}
+	return expanded
+}
+
+// Toffoli : Appends the six CX decomposition of a Toffoli gate on controls a, b and target t
+func Toffoli(out *circuit.Circuit, a, b, t int) {
+	out.H(t).
+		CNOT(b, t).Tdg(t).CNOT(a, t).T(t).
+		CNOT(b, t).Tdg(t).CNOT(a, t).T(b).T(t).H(t).
+		CNOT(a, b).T(a).Tdg(b).CNOT(a, b)
+}
+
+// multiControlled : Appends u on t controlled by every control
+func multiControlled(out *circuit.Circuit, name string, u matrix.Matrix, controls []int, t int, ancillas Ancillas) {
+	n := len(controls)
+	switch {
+	case u.Equals(gate.X(), eps):
+		multiControlledX(out, controls, t, ancillas)
+	case n <= 1:
+		out.Append(circuit.Op{Name: name, Controls: controls, Targets: []int{t}, Matrix: u})
+	case ancillas.Strategy == CleanAncilla && len(ancillas.Bits) >= n-1:
+		// compute the AND of the controls into a clean bit, use it as the only control, uncompute
+		a, rest := ancillas.Bits[0], Ancillas{CleanAncilla, ancillas.Bits[1:]}
+		multiControlledX(out, controls, a, rest)
+		out.Append(circuit.Op{Name: name, Controls: []int{a}, Targets: []int{t}, Matrix: u})
+		multiControlledX(out, controls, a, rest)
+	default:
+		roots(out, name, u, controls, t, ancillas)
+	}
+}
+
+// multiControlledX : Appends X on t controlled by every control
+func multiControlledX(out *circuit.Circuit, controls []int, t int, ancillas Ancillas) {
+	n := len(controls)
+	switch {
+	case n == 0:
+		out.X(t)
+	case n <= 2:
+		out.ControlledNot(controls, t)
+	case ancillas.Strategy == CleanAncilla && len(ancillas.Bits) >= n-2:
+		// a chain of Toffolis ANDs the controls into the ancillas, and is undone afterwards
+		a := ancillas.Bits
+		out.Toffoli(controls[0], controls[1], a[0])
+		for i := 2; i < n-1; i++ {
+			out.Toffoli(controls[i], a[i-2], a[i-1])
+		}
+		out.Toffoli(controls[n-1], a[n-3], t)
+		for i := n - 2; i >= 2; i-- {
+			out.Toffoli(controls[i], a[i-2], a[i-1])
+		}
+		out.Toffoli(controls[0], controls[1], a[0])
+	case ancillas.Strategy == DirtyAncilla && len(ancillas.Bits) >= n-2:
+		// Barenco et al. lemma 7.2, the staircase runs twice so the ancillas are restored
+		a := ancillas.Bits
+		down := func(top int) {
+			for i := top; i >= 2; i-- {
+				out.Toffoli(controls[i], a[i-2], a[i-1])
+			}
+		}
+		up := func(top int) {
+			for i := 3; i <= top; i++ {
+				out.Toffoli(controls[i-1], a[i-3], a[i-2])
+			}
+		}
+		out.Toffoli(controls[n-1], a[n-3], t)
+		down(n - 2)
+		out.Toffoli(controls[0], controls[1], a[0])
+		up(n - 1)
+		out.Toffoli(controls[n-1], a[n-3], t)
+		down(n - 2)
+		out.Toffoli(controls[0], controls[1], a[0])
+		up(n - 1)
+	default:
+		roots(out, "X", gate.X(), controls, t, ancillas)
+	}
+}
+
+// roots : Appends u on t controlled by every control using Barenco et al. lemma 7.5,
+// with V * V = u: C(V) from the last control, flip it by the rest, C(V^dagger), flip it back,
+// then V controlled by the rest
+func roots(out *circuit.Circuit, name string, u matrix.Matrix, controls []int, t int, ancillas Ancillas) {
+	n := len(controls)
+	v := root(u)
+	last, rest := controls[n-1], controls[:n-1]
+	out.Append(circuit.Op{Name: name + "^1/2", Controls: []int{last}, Targets: []int{t}, Matrix: v})
+	multiControlledX(out, rest, last, ancillas)
+	out.Append(circuit.Op{Name: name + "^-1/2", Controls: []int{last}, Targets: []int{t}, Matrix: v.Dagger()})
+	multiControlledX(out, rest, last, ancillas)
+	multiControlled(out, name+"^1/2", v, rest, t, ancillas)
+}
+
+// root : Returns a square root of a 2x2 unitary, (u + s I) / sqrt(tr u + 2 s) with s^2 = det u
+func root(u matrix.Matrix) matrix.Matrix {
+	s := cmplx.Sqrt(u[0][0]*u[1][1] - u[0][1]*u[1][0])
+	// the other root of the determinant avoids a vanishing denominator
+	if cmplx.Abs(u[0][0]+u[1][1]+2*s) < 1e-6 {
+		s = -s
+	}
+	d := cmplx.Sqrt(u[0][0] + u[1][1] + 2*s)
+	return matrix.Matrix{
+		{(u[0][0] + s) / d, u[0][1] / d},
+		{u[1][0] / d, (u[1][1] + s) / d},
+	}
+}
@@ -0,0 +1,146 @@
+package decompose
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/benluxford/qe/circuit"
+	"github.com/benluxford/qe/gate"
+	"github.com/benluxford/qe/matrix"
+	"github.com/benluxford/qe/qubit"
+)
+
+// randomState : Returns a random state on the given number of bits
+func randomState(bit int, rng *rand.Rand) *qubit.Qubit {
+	amplitudes := make([]complex128, 1<<uint(bit))
+	for i := range amplitudes {
+		amplitudes[i] = complex(rng.NormFloat64(), rng.NormFloat64())
+	}
+	return qubit.New(amplitudes...)
+}
+
+func TestMultiControlled(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	gates := []struct {
+		name string
+		u    matrix.Matrix
+	}{
+		{"X", gate.X()},
+		{"Z", gate.Z()},
+		{"S", gate.S()},
+		{"R", gate.R(3)},
+		{"U", gate.U(0.4, 1.1, -2.3, 0.9)},
+	}
+	strategies := []struct {
+		name     string
+		strategy Strategy
+		// the number of ancillas needed for n controls
+		ancillas func(n int) int
+	}{
+		{"none", NoAncilla, func(n int) int { return 0 }},
+		{"clean", CleanAncilla, func(n int) int { return n - 1 }},
+		{"dirty", DirtyAncilla, func(n int) int { return n - 2 }},
+	}
+	for _, g := range gates {
+		for _, s := range strategies {
+			for n := 2; n <= 5; n++ {
+				// controls, then the target, then the ancillas
+				k := s.ancillas(n)
+				if k < 0 {
+					k = 0
+				}
+				bit := n + 1 + k
+				controls, ancillas := []int{}, []int{}
+				for i := 0; i < n; i++ {
+					controls = append(controls, i)
+				}
+				for i := n + 1; i < bit; i++ {
+					ancillas = append(ancillas, i)
+				}
+				c, err := MultiControlled(g.name, g.u, bit, controls, n, Ancillas{s.strategy, ancillas})
+				if err != nil {
+					t.Fatal(err)
+				}
+				want := circuit.New(bit).Append(circuit.Op{Name: g.name, Controls: controls, Targets: []int{n}, Matrix: g.u})
+				for _, o := range c.Ops() {
+					if len(o.Qubits()) > 3 || len(o.Qubits()) == 3 && !(o.Name == "X" && len(o.Controls) == 2) {
+						t.Errorf("%s %s %d: %v is wider than a Toffoli", g.name, s.name, n, o)
+					}
+				}
+				for trial := 0; trial < 3; trial++ {
+					// clean ancillas start in zero, dirty ones in any state and both are restored
+					input := randomState(bit, rng)
+					if s.strategy == CleanAncilla {
+						input = qubit.TensorProduct(randomState(n+1, rng), qubit.Zero(k))
+					}
+					got, expected := c.Run(input.Clone()), want.Run(input.Clone())
+					if !got.Equals(expected, 1e-9) {
+						t.Errorf("%s %s %d: the decomposition is not the controlled gate", g.name, s.name, n)
+						break
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestCostOf(t *testing.T) {
+	controls := func(n int) (bits []int) {
+		for i := 0; i < n; i++ {
+			bits = append(bits, i)
+		}
+		return
+	}
+	ancillas := func(from, to int) (bits []int) {
+		for i := from; i < to; i++ {
+			bits = append(bits, i)
+		}
+		return
+	}
+	build := func(u matrix.Matrix, n int, a Ancillas, bit int) *circuit.Circuit {
+		c, err := MultiControlled("U", u, bit, controls(n), n, a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	tests := []struct {
+		name string
+		c    *circuit.Circuit
+		want Cost
+	}{
+		{"Toffoli", circuit.New(3).Toffoli(0, 1, 2), Cost{1, 0, 1, 1}},
+		{"expanded Toffoli", ExpandToffoli(circuit.New(3).Toffoli(0, 1, 2)), Cost{15, 6, 0, 11}},
+		{"controlled U", build(gate.H(), 1, Ancillas{}, 2), Cost{1, 1, 0, 1}},
+		// C(V) CCX C(V^dagger) CCX, then C(W) CX C(W^dagger) CX C(W) with W * W = V
+		{"three controls, no ancilla", build(gate.X(), 3, Ancillas{}, 4), Cost{9, 7, 2, 9}},
+		// a chain of 2n - 3 Toffolis
+		{"four controls, clean", build(gate.X(), 4, Ancillas{CleanAncilla, ancillas(5, 7)}, 7), Cost{5, 0, 5, 5}},
+		// the staircase twice, 4(n - 2) Toffolis
+		{"four controls, dirty", build(gate.X(), 4, Ancillas{DirtyAncilla, ancillas(5, 7)}, 7), Cost{8, 0, 8, 8}},
+		// the AND of three controls computed and uncomputed around one controlled gate, which
+		// runs alongside the last Toffoli of the computation
+		{"three controls, clean U", build(gate.S(), 3, Ancillas{CleanAncilla, ancillas(4, 6)}, 6), Cost{7, 1, 6, 6}},
+	}
+	for _, tt := range tests {
+		if got := CostOf(tt.c); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestExpandToffoli(t *testing.T) {
+	c := circuit.New(3).H(0).Toffoli(0, 1, 2).Toffoli(2, 0, 1).T(1)
+	expanded := ExpandToffoli(c)
+	if CostOf(expanded).Toffoli != 0 {
+		t.Errorf("%v still holds a Toffoli", expanded.Ops())
+	}
+	if !expanded.Unitary().Equals(c.Unitary(), 1e-9) {
+		t.Error("the expansion changed the unitary")
+	}
+	// an X with two controls holding another matrix is not a Toffoli
+	mislabelled := circuit.New(3).Append(circuit.Op{Name: "X", Controls: []int{0, 1}, Targets: []int{2}, Matrix: gate.S()})
+	if expanded := ExpandToffoli(mislabelled); expanded.Len() != 1 || !expanded.Unitary().Equals(mislabelled.Unitary(), 1e-9) {
+		t.Errorf("got %v, want the gate left alone", expanded.Ops())
+	}
+}
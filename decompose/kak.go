@@ -0,0 +1,350 @@
+package decompose
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"math/rand"
+
+	"github.com/benluxford/qe/circuit"
+	"github.com/benluxford/qe/gate"
+	"github.com/benluxford/qe/matrix"
+)
+
+// KAK : A two bit unitary written as e^(i Phase) (After[0] x After[1]) N(A, B, C) (Before[0] x Before[1]),
+// where N(A, B, C) = exp(i (A XX + B YY + C ZZ)) and Before runs first. Bit 0 is the most significant
+type KAK struct {
+	Phase   float64
+	Before  [2]matrix.Matrix
+	A, B, C float64
+	After   [2]matrix.Matrix
+}
+
+// magic : The magic basis, in which local gates are real orthogonal and N(A, B, C) is diagonal
+var magic = matrix.Matrix{
+	{1 / math.Sqrt2, 0, 0, 1i / math.Sqrt2},
+	{0, 1i / math.Sqrt2, 1 / math.Sqrt2, 0},
+	{0, 1i / math.Sqrt2, -1 / math.Sqrt2, 0},
+	{1 / math.Sqrt2, 0, 0, -1i / math.Sqrt2},
+}
+
+// KAKDecompose : Returns the KAK decomposition of a 4x4 unitary, with A, B and C each
+// brought into (-pi/4, pi/4]
+func KAKDecompose(input matrix.Matrix) (k KAK, err error) {
+	if len(input) != 4 || !input.IsUnitary(eps) {
+		return k, fmt.Errorf("decompose: expected a 4x4 unitary")
+	}
+	// scale into SU(4) and move into the magic basis
+	det := input.Determinant()
+	k.Phase = cmplx.Phase(det) / 4
+	scaled := input.Multiply(cmplx.Exp(complex(0, -k.Phase)))
+	u := product(magic.Dagger(), scaled, magic)
+
+	// u^T u is symmetric unitary, its real and imaginary parts commute and share real eigenvectors
+	p, err := diagonalise(product(u.Transpose(), u))
+	if err != nil {
+		return k, err
+	}
+	// eigenphases of u^T u are 2 theta
+	d := product(p.Transpose(), u.Transpose(), u, p)
+	theta := make([]float64, 4)
+	var sum float64
+	for i := range theta {
+		theta[i] = cmplx.Phase(d[i][i]) / 2
+		sum += theta[i]
+	}
+	// the phases must sum to a multiple of 2 pi for both orthogonal factors to be in SO(4)
+	if math.Abs(math.Remainder(sum, 2*math.Pi)) > 1 {
+		theta[0] += math.Pi
+	}
+	f := make(matrix.Matrix, 4)
+	for i := range f {
+		f[i] = make([]complex128, 4)
+		f[i][i] = cmplx.Exp(complex(0, -theta[i]))
+	}
+	// u = k1 diag(e^(i theta)) k2 with k2 = p^T and k1 = u p diag(e^(-i theta))
+	k1, k2 := product(u, p, f), p.Transpose()
+
+	// N(A, B, C) has eigenphases A - B + C, A + B - C, -A - B - C, -A + B + C in the magic basis
+	k.A = (theta[0] + theta[1] - theta[2] - theta[3]) / 4
+	k.B = (-theta[0] + theta[1] - theta[2] + theta[3]) / 4
+	k.C = (theta[0] - theta[1] - theta[2] + theta[3]) / 4
+	k.Phase += (theta[0] + theta[1] + theta[2] + theta[3]) / 4
+
+	after, before := product(magic, k1, magic.Dagger()), product(magic, k2, magic.Dagger())
+	// bring each coordinate into (-pi/4, pi/4], a step of pi/2 is the local i XX, i YY or i ZZ
+	paulis := []matrix.Matrix{gate.X(), gate.Y(), gate.Z()}
+	for i, coordinate := range []*float64{&k.A, &k.B, &k.C} {
+		steps := math.Round(*coordinate / (math.Pi / 2))
+		if *coordinate-steps*math.Pi/2 <= -math.Pi/4+eps {
+			steps--
+		}
+		*coordinate -= steps * math.Pi / 2
+		k.Phase += steps * math.Pi / 2
+		if int(steps)%2 != 0 {
+			before = product(paulis[i].TensorProduct(paulis[i]), before)
+		}
+	}
+	if k.After[0], k.After[1], err = factor(after); err != nil {
+		return
+	}
+	k.Before[0], k.Before[1], err = factor(before)
+	return
+}
+
+// Unitary : Returns the 4x4 matrix the decomposition describes
+func (k KAK) Unitary() matrix.Matrix {
+	n := product(canonical(k.A, k.B, k.C))
+	return product(k.After[0].TensorProduct(k.After[1]), n, k.Before[0].TensorProduct(k.Before[1])).
+		Multiply(cmplx.Exp(complex(0, k.Phase)))
+}
+
+// CNOTs : Returns the smallest number of CNOTs needed to build the unitary
+func (k KAK) CNOTs() int {
+	zeros, quarters := 0, 0
+	for _, coordinate := range []float64{k.A, k.B, k.C} {
+		switch {
+		case math.Abs(coordinate) < eps:
+			zeros++
+		case math.Abs(coordinate-math.Pi/4) < eps:
+			quarters++
+		}
+	}
+	switch {
+	case zeros == 3:
+		return 0
+	case zeros == 2 && quarters == 1:
+		return 1
+	case zeros >= 1:
+		return 2
+	}
+	return 3
+}
+
+// TwoQubit : Returns a Circuit on bit bits building the 4x4 unitary on bits a (most significant)
+// and b, from the fewest CNOTs possible and U gates
+func TwoQubit(input matrix.Matrix, bit, a, b int) (*circuit.Circuit, error) {
+	k, err := KAKDecompose(input)
+	if err != nil {
+		return nil, err
+	}
+	// the canonical part with the local gates it needs around it
+	core, left, right := k.core()
+	out := circuit.New(bit)
+	// locals that run before the core, then the core, then those after
+	if err = local(out, product(right[0], k.Before[0]), a); err != nil {
+		return nil, err
+	}
+	if err = local(out, product(right[1], k.Before[1]), b); err != nil {
+		return nil, err
+	}
+	for _, o := range core {
+		switch o.name {
+		case "CX":
+			out.CNOT(pick(o.bits[0], a, b), pick(o.bits[1], a, b))
+		default:
+			if err = local(out, o.matrix, pick(o.bits[0], a, b)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err = local(out, product(k.After[0], left[0]), a); err != nil {
+		return nil, err
+	}
+	if err = local(out, product(k.After[1], left[1]), b); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// step : An operation of a canonical template on local bit 0 or 1
+type step struct {
+	name   string
+	bits   []int
+	matrix matrix.Matrix
+}
+
+// core : Returns a template building N(A, B, C) with the fewest CNOTs, and the local gates
+// left and right such that N = (left[0] x left[1]) template (right[0] x right[1])
+func (k KAK) core() (template []step, left, right [2]matrix.Matrix) {
+	a, b, c := k.A, k.B, k.C
+	left, right = [2]matrix.Matrix{gate.I(), gate.I()}, [2]matrix.Matrix{gate.I(), gate.I()}
+	// permute the coordinates with local basis changes, N(A, B, C) = (V x V)^dagger N' (V x V)
+	// where conjugating by S swaps A and B and conjugating by Rx(pi/2) swaps B and C
+	permute := func(v matrix.Matrix) {
+		for i := range left {
+			left[i], right[i] = product(left[i], v.Dagger()), product(v, right[i])
+		}
+	}
+	swapAB := func() {
+		a, b = b, a
+		permute(gate.S())
+	}
+	swapBC := func() {
+		b, c = c, b
+		permute(gate.RX(math.Pi / 2))
+	}
+	zero := func(x float64) bool { return math.Abs(x) < eps }
+	cx := func(control, target int) step { return step{"CX", []int{control, target}, nil} }
+	on := func(bit int, m matrix.Matrix) step { return step{"U", []int{bit}, m} }
+
+	switch k.CNOTs() {
+	case 0:
+		return nil, left, right
+	case 1:
+		// bring the quarter turn to A, N(pi/4, 0, 0) is one CNOT between Hadamards
+		if !zero(b) {
+			swapAB()
+		} else if !zero(c) {
+			swapBC()
+			swapAB()
+		}
+		template = []step{
+			on(0, gate.H()), cx(0, 1),
+			on(0, gate.RZ(-math.Pi/2)), on(1, gate.RX(-math.Pi/2)), on(0, gate.H()),
+		}
+	case 2:
+		// bring a zero to B, N(A, 0, C) is CX (Rx(-2A) x Rz(-2C)) CX
+		if zero(a) {
+			swapAB()
+		} else if zero(c) {
+			swapBC()
+		}
+		template = []step{cx(0, 1), on(0, gate.RX(-2*a)), on(1, gate.RZ(-2*c)), cx(0, 1)}
+	default:
+		template = []step{
+			on(1, gate.RZ(math.Pi/2)),
+			cx(1, 0), on(0, gate.RZ(-2*c-math.Pi/2)), on(1, gate.RY(-2*a-math.Pi/2)),
+			cx(0, 1), on(1, gate.RY(2*b+math.Pi/2)),
+			cx(1, 0), on(0, gate.RZ(-math.Pi/2)),
+		}
+	}
+	return
+}
+
+// local : Appends a 2x2 unitary as a U gate, skipping identities
+func local(out *circuit.Circuit, input matrix.Matrix, bit int) error {
+	alpha, beta, gamma, delta, err := ZYZ(input)
+	if err != nil {
+		return err
+	}
+	if input.EqualsUpToPhase(gate.I(), eps) {
+		return nil
+	}
+	out.U(bit, alpha, beta, gamma, delta)
+	return nil
+}
+
+// pick : Returns a for local bit 0 and b for local bit 1
+func pick(local, a, b int) int {
+	if local == 0 {
+		return a
+	}
+	return b
+}
+
+// canonical : Returns N(A, B, C) = exp(i (A XX + B YY + C ZZ)), diagonal in the magic basis
+func canonical(a, b, c float64) matrix.Matrix {
+	phases := []float64{a - b + c, a + b - c, -a - b - c, -a + b + c}
+	d := make(matrix.Matrix, 4)
+	for i := range d {
+		d[i] = make([]complex128, 4)
+		d[i][i] = cmplx.Exp(complex(0, phases[i]))
+	}
+	return product(magic, d, magic.Dagger())
+}
+
+// product : Returns the matrix product of the inputs, in the written order
+func product(input ...matrix.Matrix) (m matrix.Matrix) {
+	m = input[len(input)-1]
+	// Apply multiplies on the left, so walk backwards
+	for i := len(input) - 2; i >= 0; i-- {
+		m = m.Apply(input[i])
+	}
+	return
+}
+
+// diagonalise : Returns a real orthogonal matrix with determinant one diagonalising the
+// complex symmetric unitary input
+func diagonalise(input matrix.Matrix) (matrix.Matrix, error) {
+	// a random mix of the commuting real and imaginary parts separates their eigenvalues
+	r := rand.New(rand.NewSource(1))
+	for attempt := 0; attempt < 16; attempt++ {
+		x, y := r.Float64(), r.Float64()
+		mixed := make(matrix.Matrix, 4)
+		for i := range mixed {
+			mixed[i] = make([]complex128, 4)
+			for j := range mixed[i] {
+				mixed[i][j] = complex(x*real(input[i][j])+y*imag(input[i][j]), 0)
+			}
+		}
+		// the mix is real symmetric, so its eigenvectors are real up to rounding
+		_, vectors := mixed.EigenHermitian()
+		orthogonal := make(matrix.Matrix, 4)
+		for i := range orthogonal {
+			orthogonal[i] = make([]complex128, 4)
+			for j := range orthogonal[i] {
+				orthogonal[i][j] = complex(real(vectors[i][j]), 0)
+			}
+		}
+		if real(orthogonal.Determinant()) < 0 {
+			for i := range orthogonal {
+				orthogonal[i][0] = -orthogonal[i][0]
+			}
+		}
+		// check the whole input is diagonal, not just the mix
+		d := product(orthogonal.Transpose(), input, orthogonal)
+		diagonal := true
+		for i := range d {
+			for j := range d[i] {
+				if i != j && cmplx.Abs(d[i][j]) > 1e-7 {
+					diagonal = false
+				}
+			}
+		}
+		if diagonal {
+			return orthogonal, nil
+		}
+	}
+	return nil, fmt.Errorf("decompose: could not diagonalise the magic basis square")
+}
+
+// factor : Returns the two 2x2 unitaries whose tensor product is the input
+func factor(input matrix.Matrix) (a, b matrix.Matrix, err error) {
+	// the largest component fixes a row and column of each factor
+	row, column := 0, 0
+	for i := range input {
+		for j := range input[i] {
+			if cmplx.Abs(input[i][j]) > cmplx.Abs(input[row][column]) {
+				row, column = i, j
+			}
+		}
+	}
+	// b is the 2x2 block holding the largest component, a is read off across blocks
+	b = matrix.Matrix{
+		{input[row&^1][column&^1], input[row&^1][column|1]},
+		{input[row|1][column&^1], input[row|1][column|1]},
+	}
+	a = matrix.Matrix{
+		{input[row&1][column&1], input[row&1][2+column&1]},
+		{input[2+row&1][column&1], input[2+row&1][2+column&1]},
+	}
+	// normalise both to unit determinant, the leftover is a sign absorbed into a
+	detB := cmplx.Sqrt(b[0][0]*b[1][1] - b[0][1]*b[1][0])
+	b = b.Multiply(1 / detB)
+	detA := cmplx.Sqrt(a[0][0]*a[1][1] - a[0][1]*a[1][0])
+	a = a.Multiply(1 / detA)
+	if !a.TensorProduct(b).EqualsUpToPhase(input, 1e-7) {
+		return nil, nil, fmt.Errorf("decompose: matrix is not a tensor product")
+	}
+	// restore the exact phase on a
+	var overlap complex128
+	ab := a.TensorProduct(b)
+	for i := range ab {
+		for j := range ab[i] {
+			overlap += cmplx.Conj(ab[i][j]) * input[i][j]
+		}
+	}
+	a = a.Multiply(overlap / complex(cmplx.Abs(overlap), 0))
+	return
+}
@@ -0,0 +1,50 @@
+package decompose
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/benluxford/qe/circuit"
+	"github.com/benluxford/qe/matrix"
+)
+
+func TestTwoQubit(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	angle := func() float64 { return (rng.Float64()*2 - 1) * 3.2 }
+	inputs := []*circuit.Circuit{
+		circuit.New(2).CNOT(0, 1),
+		circuit.New(2).Swap(0, 1),
+		circuit.New(2).CZ(0, 1).H(1),
+		circuit.New(2).H(0).X(1),
+	}
+	for i := 0; i < 20; i++ {
+		c := circuit.New(2)
+		for layer := 0; layer < 4; layer++ {
+			c.U3(0, angle(), angle(), angle()).U3(1, angle(), angle(), angle()).CNOT(layer%2, 1-layer%2)
+		}
+		inputs = append(inputs, c)
+	}
+	for i, c := range inputs {
+		u := c.Unitary()
+		k, err := KAKDecompose(u)
+		if err != nil {
+			t.Fatalf("input %d: %v", i, err)
+		}
+		if !k.Unitary().Equals(u, 1e-8) {
+			t.Errorf("input %d: KAK does not rebuild the unitary", i)
+		}
+		out, err := TwoQubit(u, 2, 0, 1)
+		if err != nil {
+			t.Fatalf("input %d: %v", i, err)
+		}
+		if !out.Unitary().EqualsUpToPhase(u, 1e-8) {
+			t.Errorf("input %d: circuit does not build the unitary", i)
+		}
+		if got := CostOf(out).TwoBit; got != k.CNOTs() || got > 3 {
+			t.Errorf("input %d: %d CNOTs, want %d", i, got, k.CNOTs())
+		}
+	}
+	if _, err := KAKDecompose(matrix.Matrix{{1, 0}, {0, 1}}); err == nil {
+		t.Error("expected an error for a 2x2 input")
+	}
+}
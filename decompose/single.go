@@ -0,0 +1,94 @@
+package decompose
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+
+	"github.com/benluxford/qe/matrix"
+)
+
+// eps : The tolerance used for unitarity checks and degenerate angles
+const eps = 1e-9
+
+// ZYZ : Returns the angles with gate.U(alpha, beta, gamma, delta) equal to the input, that is
+// e^(i alpha) Rz(delta) Ry(gamma) Rz(beta), where Rz(beta) is applied first
+func ZYZ(input matrix.Matrix) (alpha, beta, gamma, delta float64, err error) {
+	if err = single(input); err != nil {
+		return
+	}
+	alpha, delta, gamma, beta = zyz(input)
+	return
+}
+
+// ZXZ : Returns the angles with e^(i alpha) Rz(delta) Rx(gamma) Rz(beta) equal to the input,
+// where Rz(beta) is applied first
+func ZXZ(input matrix.Matrix) (alpha, beta, gamma, delta float64, err error) {
+	if alpha, beta, gamma, delta, err = ZYZ(input); err != nil {
+		return
+	}
+	// Ry(gamma) is Rz(pi/2) Rx(gamma) Rz(-pi/2), the outer rotations absorb the turn
+	beta, delta = beta-math.Pi/2, delta+math.Pi/2
+	return
+}
+
+// U3 : Returns the angles with e^(i phase) gate.U3(theta, phi, lambda) equal to the input
+func U3(input matrix.Matrix) (theta, phi, lambda, phase float64, err error) {
+	var alpha float64
+	if alpha, lambda, theta, phi, err = ZYZ(input); err != nil {
+		return
+	}
+	// U3 carries a phase of (phi + lambda) / 2 over the Euler rotations
+	phase = alpha - (phi+lambda)/2
+	return
+}
+
+// Format : Returns the single bit gate written as its U3 angles and global phase,
+// e.g. "e^(i0.7854) U3(1.571, 0, 3.142)"
+func Format(input matrix.Matrix) (string, error) {
+	theta, phi, lambda, phase, err := U3(input)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("e^(i%.4g) U3(%.4g, %.4g, %.4g)", tidy(phase), tidy(theta), tidy(phi), tidy(lambda)), nil
+}
+
+// single : Returns an error unless the input is a 2x2 unitary
+func single(input matrix.Matrix) error {
+	if len(input) != 2 || len(input[0]) != 2 || len(input[1]) != 2 {
+		return fmt.Errorf("decompose: expected a 2x2 matrix, got %d rows", len(input))
+	}
+	if !input.IsUnitary(eps) {
+		return fmt.Errorf("decompose: matrix is not unitary")
+	}
+	return nil
+}
+
+// zyz : Returns the angles of a 2x2 unitary as e^(i phase) Rz(left) Ry(gamma) Rz(right)
+func zyz(input matrix.Matrix) (phase, left, gamma, right float64) {
+	// remove the global phase so the matrix is special unitary
+	det := input[0][0]*input[1][1] - input[0][1]*input[1][0]
+	phase = cmplx.Phase(det) / 2
+	e := cmplx.Exp(complex(0, -phase))
+	v00, v10, v11 := input[0][0]*e, input[1][0]*e, input[1][1]*e
+	gamma = 2 * math.Atan2(cmplx.Abs(v10), cmplx.Abs(v00))
+	// left + right comes from the diagonal, left - right from the off diagonal
+	switch {
+	case cmplx.Abs(v10) < eps:
+		left, right = cmplx.Phase(v11), cmplx.Phase(v11)
+	case cmplx.Abs(v00) < eps:
+		left, right = cmplx.Phase(v10), -cmplx.Phase(v10)
+	default:
+		sum, difference := 2*cmplx.Phase(v11), 2*cmplx.Phase(v10)
+		left, right = (sum+difference)/2, (sum-difference)/2
+	}
+	return
+}
+
+// tidy : Returns the angle with values within eps of zero printed as zero
+func tidy(angle float64) float64 {
+	if math.Abs(angle) < eps {
+		return 0
+	}
+	return angle
+}
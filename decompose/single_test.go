@@ -0,0 +1,122 @@
+package decompose
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"math/rand"
+	"testing"
+
+	"github.com/benluxford/qe/gate"
+	"github.com/benluxford/qe/matrix"
+)
+
+// times : Returns the matrix product of the inputs, in the written order
+func times(input ...matrix.Matrix) (m matrix.Matrix) {
+	m = input[len(input)-1]
+	for i := len(input) - 2; i >= 0; i-- {
+		m = m.Apply(input[i])
+	}
+	return
+}
+
+// singleInputs : Returns random, diagonal and anti diagonal 2x2 unitaries
+func singleInputs(rng *rand.Rand) (inputs []matrix.Matrix) {
+	phase := func() complex128 { return cmplx.Exp(complex(0, (rng.Float64()*2-1)*math.Pi)) }
+	for i := 0; i < 50; i++ {
+		// e^(i phi) [[a, -b*], [b, a*]] with |a|^2 + |b|^2 = 1
+		a, b := complex(rng.NormFloat64(), rng.NormFloat64()), complex(rng.NormFloat64(), rng.NormFloat64())
+		norm := complex(math.Hypot(cmplx.Abs(a), cmplx.Abs(b)), 0)
+		a, b = a/norm, b/norm
+		inputs = append(inputs, matrix.Matrix{{a, -cmplx.Conj(b)}, {b, cmplx.Conj(a)}}.Multiply(phase()))
+	}
+	for i := 0; i < 10; i++ {
+		inputs = append(inputs, matrix.Matrix{{phase(), 0}, {0, phase()}}, matrix.Matrix{{0, phase()}, {phase(), 0}})
+	}
+	return append(inputs, gate.I(), gate.I().Multiply(-1), gate.X(), gate.Y(), gate.Z(), gate.H(),
+		gate.S(), gate.T(), gate.SX(), gate.RY(math.Pi), gate.RX(-math.Pi))
+}
+
+func TestSingle(t *testing.T) {
+	for i, u := range singleInputs(rand.New(rand.NewSource(1))) {
+		alpha, beta, gamma, delta, err := ZYZ(u)
+		if err != nil {
+			t.Fatalf("input %d: %v", i, err)
+		}
+		if !gate.U(alpha, beta, gamma, delta).Equals(u, 1e-9) {
+			t.Errorf("input %d: ZYZ angles do not rebuild %v", i, u)
+		}
+		alpha, beta, gamma, delta, err = ZXZ(u)
+		if err != nil {
+			t.Fatalf("input %d: %v", i, err)
+		}
+		zxz := times(gate.RZ(delta), gate.RX(gamma), gate.RZ(beta)).Multiply(cmplx.Exp(complex(0, alpha)))
+		if !zxz.Equals(u, 1e-9) {
+			t.Errorf("input %d: ZXZ angles do not rebuild %v", i, u)
+		}
+		theta, phi, lambda, phase, err := U3(u)
+		if err != nil {
+			t.Fatalf("input %d: %v", i, err)
+		}
+		u3 := gate.U3(theta, phi, lambda)
+		if !u3.Multiply(cmplx.Exp(complex(0, phase))).Equals(u, 1e-9) || !u3.EqualsUpToPhase(u, 1e-9) {
+			t.Errorf("input %d: U3 angles do not rebuild %v", i, u)
+		}
+		// the printed angles keep four significant figures, each off by up to 5e-4
+		formatted, err := Format(u)
+		if err != nil {
+			t.Fatalf("input %d: %v", i, err)
+		}
+		if _, err := fmt.Sscanf(formatted, "e^(i%g) U3(%g, %g, %g)", &phase, &theta, &phi, &lambda); err != nil {
+			t.Fatalf("input %d: %q: %v", i, formatted, err)
+		}
+		if !gate.U3(theta, phi, lambda).Multiply(cmplx.Exp(complex(0, phase))).Equals(u, 5e-3) {
+			t.Errorf("input %d: %q does not rebuild %v", i, formatted, u)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		u    matrix.Matrix
+		want string
+	}{
+		{gate.I(), "e^(i0) U3(0, 0, 0)"},
+		// i [[0, -i], [-i, 0]]
+		{gate.X(), "e^(i1.571) U3(3.142, -1.571, 1.571)"},
+		{gate.H(), "e^(i0) U3(1.571, 0, 3.142)"},
+		{gate.T(), "e^(i0) U3(0, 0.3927, 0.3927)"},
+	}
+	for _, tt := range tests {
+		got, err := Format(tt.u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("Format(%v) = %q, want %q", tt.u, got, tt.want)
+		}
+	}
+}
+
+func TestSingleRejectsNonUnitary(t *testing.T) {
+	inputs := []matrix.Matrix{
+		{{1, 1}, {0, 1}},
+		{{2, 0}, {0, 0.5}},
+		{{1}},
+		gate.I(2),
+	}
+	for _, u := range inputs {
+		if _, _, _, _, err := ZYZ(u); err == nil {
+			t.Errorf("ZYZ(%v): expected an error", u)
+		}
+		if _, _, _, _, err := ZXZ(u); err == nil {
+			t.Errorf("ZXZ(%v): expected an error", u)
+		}
+		if _, _, _, _, err := U3(u); err == nil {
+			t.Errorf("U3(%v): expected an error", u)
+		}
+		if _, err := Format(u); err == nil {
+			t.Errorf("Format(%v): expected an error", u)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package diagram
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// blochRadius : The radius of the text Bloch sphere in lines
+const blochRadius = 5
+
+// BlochText : Returns the Bloch vector drawn on the x-z plane of a text sphere, with |0> at the
+// top and |1> at the bottom. The point is ● in front of the plane (y >= 0) and ○ behind it,
+// followed by the components and the length of the vector
+func BlochText(x, y, z float64) string {
+	// characters are about twice as tall as they are wide
+	rows, cols := 2*blochRadius+1, 4*blochRadius+1
+	grid := make([][]rune, rows)
+	for i := range grid {
+		grid[i] = []rune(strings.Repeat(" ", cols))
+	}
+	// outline, then axes
+	for t := 0; t < 360; t += 2 {
+		a := float64(t) * math.Pi / 180
+		r := blochRadius - int(math.Round(blochRadius*math.Sin(a)))
+		c := 2*blochRadius + int(math.Round(2*blochRadius*math.Cos(a)))
+		grid[r][c] = '·'
+	}
+	for c := 1; c < cols-1; c++ {
+		grid[blochRadius][c] = '─'
+	}
+	for r := 1; r < rows-1; r++ {
+		grid[r][2*blochRadius] = '│'
+	}
+	grid[blochRadius][2*blochRadius] = '┼'
+	point := '●'
+	if y < 0 {
+		point = '○'
+	}
+	// rounding can leave a pure state a little outside the sphere, which must not leave the grid
+	clamp := func(v float64) float64 {
+		return math.Max(-1, math.Min(1, v))
+	}
+	grid[blochRadius-int(math.Round(blochRadius*clamp(z)))][2*blochRadius+int(math.Round(2*blochRadius*clamp(x)))] = point
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%*s\n", 2*blochRadius+3, "|0>"))
+	for i, row := range grid {
+		line := string(row)
+		if i == blochRadius {
+			line += " x"
+		}
+		b.WriteString(strings.TrimRight(line, " ") + "\n")
+	}
+	b.WriteString(fmt.Sprintf("%*s\n", 2*blochRadius+3, "|1>"))
+	b.WriteString(fmt.Sprintf("x=%.3f y=%.3f z=%.3f |r|=%.3f\n", x, y, z, math.Sqrt(x*x+y*y+z*z)))
+	return b.String()
+}
+
+// BlochSVG : Returns the Bloch vector drawn on a sphere as a standalone SVG document, in an
+// oblique view with x towards the viewer, y to the right and z up
+func BlochSVG(x, y, z float64) string {
+	const size, r = 240.0, 90.0
+	cx, cy := size/2, size/2
+	// project a point of the unit sphere onto the page
+	project := func(x, y, z float64) (float64, float64) {
+		return cx + r*(y-0.4*x), cy - r*(z-0.3*x)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%g" height="%g" viewBox="0 0 %g %g" font-family="serif" font-size="14">`+"\n", size, size, size, size)
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="white"/>`+"\n")
+	fmt.Fprintf(&b, `<circle cx="%g" cy="%g" r="%g" fill="#eef" stroke="black"/>`+"\n", cx, cy, r)
+	// the equator as a polyline through the projected circle
+	points := []string{}
+	for t := 0; t <= 64; t++ {
+		a := 2 * math.Pi * float64(t) / 64
+		px, py := project(math.Cos(a), math.Sin(a), 0)
+		points = append(points, fmt.Sprintf("%.2f,%.2f", px, py))
+	}
+	fmt.Fprintf(&b, `<polyline points="%s" fill="none" stroke="gray" stroke-dasharray="3,3"/>`+"\n", strings.Join(points, " "))
+	axes := []struct {
+		name    string
+		x, y, z float64
+	}{{"x", 1, 0, 0}, {"y", 0, 1, 0}, {"|0⟩", 0, 0, 1}, {"|1⟩", 0, 0, -1}}
+	for _, a := range axes {
+		px, py := project(a.x, a.y, a.z)
+		fmt.Fprintf(&b, `<line x1="%g" y1="%g" x2="%.2f" y2="%.2f" stroke="gray"/>`+"\n", cx, cy, px, py)
+		lx, ly := project(1.15*a.x, 1.15*a.y, 1.15*a.z)
+		fmt.Fprintf(&b, `<text x="%.2f" y="%.2f" text-anchor="middle" dominant-baseline="middle">%s</text>`+"\n", lx, ly, escape(a.name))
+	}
+	// the state vector itself
+	px, py := project(x, y, z)
+	fmt.Fprintf(&b, `<line x1="%g" y1="%g" x2="%.2f" y2="%.2f" stroke="crimson" stroke-width="2"/>`+"\n", cx, cy, px, py)
+	fmt.Fprintf(&b, `<circle cx="%.2f" cy="%.2f" r="4" fill="crimson"/>`+"\n", px, py)
+	fmt.Fprintf(&b, `<text x="%g" y="%g" text-anchor="middle" font-size="11">(%.3f, %.3f, %.3f)</text>`+"\n", cx, size-8, x, y, z)
+	b.WriteString("</svg>\n")
+	return b.String()
+}
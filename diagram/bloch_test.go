@@ -0,0 +1,50 @@
+package diagram
+
+import (
+	"strings"
+	"testing"
+)
+
+// sphere : Returns the drawing of BlochText without the line of components
+func sphere(text string) string {
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	return strings.Join(lines[:len(lines)-1], "\n")
+}
+
+func TestBlochTextClamps(t *testing.T) {
+	tests := []struct {
+		name    string
+		x, y, z float64
+		inside  [3]float64
+	}{
+		{"above |0>", 0, 0, 1.2, [3]float64{0, 0, 1}},
+		{"below |1>", 0, 0, -1.2, [3]float64{0, 0, -1}},
+		{"past x", 1.06, 0, 0, [3]float64{1, 0, 0}},
+		{"past -x", -1.06, 0.1, 0, [3]float64{-1, 0.1, 0}},
+	}
+	for _, tt := range tests {
+		got := sphere(BlochText(tt.x, tt.y, tt.z))
+		if want := sphere(BlochText(tt.inside[0], tt.inside[1], tt.inside[2])); got != want {
+			t.Errorf("%s: got\n%s\nwant\n%s", tt.name, got, want)
+		}
+	}
+}
+
+func TestBlochText(t *testing.T) {
+	golden(t, "|+>", BlochText(1, 0, 0), `
+          |0>
+      ·········
+   ····   │   ····
+  ··      │      ··
+··        │        ··
+·         │         ·
+·─────────┼─────────● x
+·         │         ·
+··        │        ··
+ ···      │      ···
+   ····   │   ····
+      ·········
+          |1>
+x=1.000 y=0.000 z=0.000 |r|=1.000
+`)
+}
@@ -0,0 +1,121 @@
+package diagram
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/benluxford/qe/circuit"
+)
+
+// Quantikz : Returns the Circuit as a LaTeX quantikz environment, with the same columns as Text.
+// Parameters are written as multiples of \pi where possible, barriers become slices and
+// measurements are joined to a classical wire below the qubits
+func Quantikz(c *circuit.Circuit) string {
+	n := c.NumberOfBit()
+	names := wireNames(c)
+	packed := layers(c)
+	// each cell starts as a plain wire, quantum or classical
+	cells := make([][]string, len(names))
+	for i := range cells {
+		cells[i] = make([]string, len(packed)+1)
+		for j := range cells[i] {
+			cells[i][j] = `\qw`
+			if i == n {
+				cells[i][j] = `\cw`
+			}
+		}
+	}
+
+	for j, layer := range packed {
+		for _, o := range layer {
+			if o.Name == "Barrier" {
+				// a barrier on no qubits has nowhere to go
+				if lo, hi := span(c, o); lo <= hi {
+					cells[lo][j] += ` \slice{}`
+				}
+				continue
+			}
+			if o.Name == "Measure" {
+				q := o.Targets[0]
+				cells[q][j] = fmt.Sprintf(`\meter{} \vcw{%d}`, n-q)
+				continue
+			}
+			if o.Name == "Reset" {
+				cells[o.Targets[0]][j] = `\gate{\lvert 0 \rangle}`
+				continue
+			}
+			for _, q := range o.Controls {
+				cells[q][j] = `\control{}`
+			}
+			for k, q := range o.Targets {
+				switch symbol(o) {
+				case '⊕':
+					cells[q][j] = `\targ{}`
+				case '×':
+					cells[q][j] = `\targX{}`
+				default:
+					text := latexLabel(o)
+					if len(o.Targets) > 1 {
+						text = fmt.Sprintf("{%s}_{%d}", text, k)
+					}
+					cells[q][j] = `\gate{` + text + `}`
+				}
+			}
+			// join each element of the operation to the next one down
+			qubits := append([]int{}, o.Qubits()...)
+			sort.Ints(qubits)
+			for i := 0; i+1 < len(qubits); i++ {
+				q, d := qubits[i], qubits[i+1]-qubits[i]
+				if cells[q][j] == `\control{}` {
+					cells[q][j] = fmt.Sprintf(`\ctrl{%d}`, d)
+					continue
+				}
+				cells[q][j] += fmt.Sprintf(` \vqw{%d}`, d)
+			}
+			// a condition is read off the classical wire up a classical line
+			if o.Condition != nil {
+				q := qubits[len(qubits)-1]
+				cells[q][j] += fmt.Sprintf(` \vcw{%d}`, n-q)
+				cells[n][j] = `\push{\scriptstyle ` + strings.ReplaceAll(condition(c, o), "=", " = ") + `}`
+			}
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(`\begin{quantikz}` + "\n")
+	for i, row := range cells {
+		name := strings.TrimSuffix(names[i], ": ")
+		b.WriteString(`\lstick{$` + name + `$} & ` + strings.Join(row, " & "))
+		if i < len(cells)-1 {
+			b.WriteString(` \\`)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(`\end{quantikz}` + "\n")
+	return b.String()
+}
+
+// latexLabel : Returns the math mode label of a gate, e.g. R_z(\pi/2) or S^\dagger
+func latexLabel(o circuit.Op) string {
+	names := map[string]string{
+		"RX": "R_x", "RY": "R_y", "RZ": "R_z", "U3": "U_3",
+		"Sdg": `S^\dagger`, "Tdg": `T^\dagger`, "SX": `\sqrt{X}`,
+	}
+	name, ok := names[o.Name]
+	switch {
+	case ok:
+	case len([]rune(o.Name)) > 1:
+		name = `\mathrm{` + o.Name + `}`
+	default:
+		name = o.Name
+	}
+	if len(o.Params) == 0 {
+		return name
+	}
+	params := []string{}
+	for _, p := range o.Params {
+		params = append(params, angle(p, `\pi`))
+	}
+	return name + "(" + strings.Join(params, ", ") + ")"
+}
@@ -0,0 +1,65 @@
+package diagram
+
+import (
+	"math"
+	"testing"
+
+	"github.com/benluxford/qe/circuit"
+)
+
+func TestQuantikz(t *testing.T) {
+	tests := []struct {
+		name string
+		c    *circuit.Circuit
+		want string
+	}{
+		{"bell", circuit.New(2).H(0).CNOT(0, 1).MeasureAll(), `
+\begin{quantikz}
+\lstick{$q0$} & \gate{H} & \ctrl{1} & \meter{} \vcw{2} & \qw & \qw \\
+\lstick{$q1$} & \qw & \targ{} & \qw & \meter{} \vcw{1} & \qw \\
+\lstick{$c$} & \cw & \cw & \cw & \cw & \cw
+\end{quantikz}
+`},
+		{"parameters, swap and barrier", circuit.New(3).RZ(0, math.Pi/2).Sdg(1).Swap(0, 2).Barrier().CS(2, 1).U3(0, math.Pi, 0, -math.Pi/4).Measure(1, 0), `
+\begin{quantikz}
+\lstick{$q0$} & \gate{R_z(\pi/2)} & \targX{} \vqw{2} & \qw \slice{} & \gate{U_3(\pi, 0, -\pi/4)} & \qw & \qw \\
+\lstick{$q1$} & \gate{S^\dagger} & \qw & \qw & \gate{S} \vqw{1} & \meter{} \vcw{2} & \qw \\
+\lstick{$q2$} & \qw & \targX{} & \qw & \control{} & \qw & \qw \\
+\lstick{$c$} & \cw & \cw & \cw & \cw & \cw & \cw
+\end{quantikz}
+`},
+		{"empty", circuit.New(0).Barrier(), `
+\begin{quantikz}
+\end{quantikz}
+`},
+	}
+	for _, tt := range tests {
+		golden(t, tt.name, Quantikz(tt.c), tt.want)
+	}
+}
+
+func TestQuantikzRegisters(t *testing.T) {
+	c := circuit.New(0)
+	data := c.QuantumRegister("data", 2)
+	out := c.ClassicalRegister("out", 2)
+	c.H(data.At(0)).CNOT(data.At(0), data.At(1)).Measure(data.At(0), out.At(0)).Measure(data.At(1), out.At(1))
+	c.If(out.Bits(), 3, circuit.New(2).X(1))
+	golden(t, "registers", Quantikz(c), `
+\begin{quantikz}
+\lstick{$data[0]$} & \gate{H} & \ctrl{1} & \meter{} \vcw{2} & \qw & \qw & \qw \\
+\lstick{$data[1]$} & \qw & \targ{} & \qw & \meter{} \vcw{1} & \gate{X} \vcw{1} & \qw \\
+\lstick{$c$} & \cw & \cw & \cw & \cw & \push{\scriptstyle out = 3} & \cw
+\end{quantikz}
+`)
+}
+
+func TestQuantikzConditions(t *testing.T) {
+	c := circuit.New(2).H(0).Measure(0, 0).If([]int{0}, 1, circuit.New(2).Z(1))
+	golden(t, "conditions", Quantikz(c), `
+\begin{quantikz}
+\lstick{$q0$} & \gate{H} & \meter{} \vcw{2} & \qw & \qw \\
+\lstick{$q1$} & \qw & \qw & \gate{Z} \vcw{1} & \qw \\
+\lstick{$c$} & \cw & \cw & \push{\scriptstyle c0 = 1} & \cw
+\end{quantikz}
+`)
+}
@@ -0,0 +1,198 @@
+package diagram
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/benluxford/qe/circuit"
+)
+
+// The geometry of SVG drawings in pixels
+const (
+	rowHeight = 40
+	boxHeight = 30
+	charWidth = 8
+	margin    = 10
+)
+
+// SVG : Returns the Circuit drawn as a standalone SVG document, with the same layout as Text:
+// boxed gates labelled with their parameters as multiples of π where possible, dots for
+// controls, ⊕ and × targets, dashed barriers, and measurements joined to a double classical wire
+func SVG(c *circuit.Circuit) string {
+	n := c.NumberOfBit()
+	names := wireNames(c)
+	left := 0
+	for _, name := range names {
+		if w := charWidth * len([]rune(name)); w > left {
+			left = w
+		}
+	}
+	left += margin
+
+	// columns are as wide as their widest box
+	packed := layers(c)
+	centres := make([]float64, len(packed))
+	x := float64(left + margin)
+	for i, layer := range packed {
+		w := float64(boxHeight)
+		for _, o := range layer {
+			if symbol(o) != 0 {
+				continue
+			}
+			if b := boxWidth(svgLabel(o), len(o.Targets)); b > w {
+				w = b
+			}
+		}
+		centres[i] = x + w/2
+		x += w + margin
+	}
+	width, height := x+margin, float64(rowHeight*len(names)+2*margin)
+	y := func(wire int) float64 {
+		return float64(margin + rowHeight*wire + rowHeight/2)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%g" height="%g" viewBox="0 0 %g %g" font-family="serif" font-size="14">`+"\n", width, height, width, height)
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="white"/>`+"\n")
+	// wires, with a double line for the classical bits
+	for i, name := range names {
+		fmt.Fprintf(&b, `<text x="%d" y="%g" text-anchor="end" dominant-baseline="middle">%s</text>`+"\n", left-margin/2, y(i), escape(strings.TrimSuffix(name, ": ")))
+		if i < n {
+			line(&b, float64(left), y(i), width-margin, y(i), "")
+			continue
+		}
+		line(&b, float64(left), y(i)-2, width-margin, y(i)-2, "")
+		line(&b, float64(left), y(i)+2, width-margin, y(i)+2, "")
+	}
+
+	for i, layer := range packed {
+		cx := centres[i]
+		for _, o := range layer {
+			lo, hi := span(c, o)
+			switch {
+			case o.Name == "Barrier":
+				for _, q := range o.Targets {
+					fmt.Fprintf(&b, `<rect x="%g" y="%g" width="10" height="%d" fill="#ddd"/>`+"\n", cx-5, y(q)-rowHeight/2, rowHeight)
+					line(&b, cx, y(q)-rowHeight/2, cx, y(q)+rowHeight/2, ` stroke-dasharray="4,3"`)
+				}
+				continue
+			case len(o.Clbits) > 0:
+				// the result travels down a double line to the classical wire
+				line(&b, cx-2, y(lo), cx-2, y(hi)-2, "")
+				line(&b, cx+2, y(lo), cx+2, y(hi)-2, "")
+				for _, bit := range o.Clbits {
+					fmt.Fprintf(&b, `<path d="M %g %g L %g %g L %g %g Z"/>`+"\n", cx-5, y(hi)-8, cx+5, y(hi)-8, cx, y(hi))
+					fmt.Fprintf(&b, `<text x="%g" y="%g" text-anchor="middle" font-size="10">%d</text>`+"\n", cx, y(hi)+14, bit)
+				}
+			case o.Condition != nil:
+				// the condition is read off the classical wire up a double line
+				line(&b, cx-2, y(lo), cx-2, y(hi), "")
+				line(&b, cx+2, y(lo), cx+2, y(hi), "")
+				fmt.Fprintf(&b, `<circle cx="%g" cy="%g" r="5"/>`+"\n", cx, y(hi))
+				fmt.Fprintf(&b, `<text x="%g" y="%g" text-anchor="middle" font-size="10">%s</text>`+"\n", cx, y(hi)+16, escape(condition(c, o)))
+			case hi > lo:
+				line(&b, cx, y(lo), cx, y(hi), "")
+			}
+			for _, q := range o.Controls {
+				fmt.Fprintf(&b, `<circle cx="%g" cy="%g" r="5"/>`+"\n", cx, y(q))
+			}
+			for k, q := range o.Targets {
+				switch symbol(o) {
+				case '⊕':
+					fmt.Fprintf(&b, `<circle cx="%g" cy="%g" r="11" fill="white" stroke="black"/>`+"\n", cx, y(q))
+					line(&b, cx-11, y(q), cx+11, y(q), "")
+					line(&b, cx, y(q)-11, cx, y(q)+11, "")
+				case '×':
+					line(&b, cx-7, y(q)-7, cx+7, y(q)+7, "")
+					line(&b, cx-7, y(q)+7, cx+7, y(q)-7, "")
+				default:
+					text := svgLabel(o)
+					if len(o.Targets) > 1 {
+						text += fmt.Sprintf(":%d", k)
+					}
+					w := boxWidth(svgLabel(o), len(o.Targets))
+					fmt.Fprintf(&b, `<rect x="%g" y="%g" width="%g" height="%d" fill="white" stroke="black"/>`+"\n", cx-w/2, y(q)-boxHeight/2, w, boxHeight)
+					if o.Name == "Measure" {
+						meter(&b, cx, y(q))
+						continue
+					}
+					fmt.Fprintf(&b, `<text x="%g" y="%g" text-anchor="middle" dominant-baseline="middle">%s</text>`+"\n", cx, y(q), escape(text))
+				}
+			}
+		}
+	}
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+// svgLabel : Returns the text of a box, with parameters as multiples of π where possible
+func svgLabel(o circuit.Op) string {
+	switch o.Name {
+	case "Measure":
+		return "M"
+	case "Reset":
+		return "|0⟩"
+	}
+	if len(o.Params) == 0 {
+		return o.Name
+	}
+	params := []string{}
+	for _, p := range o.Params {
+		params = append(params, angle(p, "π"))
+	}
+	return o.Name + "(" + strings.Join(params, ", ") + ")"
+}
+
+// boxWidth : Returns the width of a box holding the label, with room for a target index
+func boxWidth(label string, targets int) float64 {
+	l := len([]rune(label))
+	if targets > 1 {
+		l += 1 + len(fmt.Sprint(targets-1))
+	}
+	return math.Max(boxHeight, float64(charWidth*l+2*margin))
+}
+
+// meter : Draws the dial and needle of a measurement inside its box
+func meter(b *strings.Builder, cx, cy float64) {
+	fmt.Fprintf(b, `<path d="M %g %g A 9 9 0 0 1 %g %g" fill="none" stroke="black"/>`+"\n", cx-9, cy+6, cx+9, cy+6)
+	line(b, cx, cy+6, cx+7, cy-8, "")
+}
+
+// line : Draws a straight black line with any extra attributes
+func line(b *strings.Builder, x1, y1, x2, y2 float64, attributes string) {
+	fmt.Fprintf(b, `<line x1="%g" y1="%g" x2="%g" y2="%g" stroke="black"%s/>`+"\n", x1, y1, x2, y2, attributes)
+}
+
+// escape : Returns the text with the characters XML reserves escaped
+func escape(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;").Replace(text)
+}
+
+// angle : Returns the parameter as a small fraction of π written with the given symbol,
+// e.g. "π/2" or "-3π/4", falling back to three significant figures
+func angle(p float64, pi string) string {
+	if math.Abs(p) < 1e-9 {
+		return "0"
+	}
+	for _, denominator := range []int{1, 2, 3, 4, 6, 8} {
+		numerator := p * float64(denominator) / math.Pi
+		rounded := math.Round(numerator)
+		if rounded == 0 || math.Abs(numerator-rounded) > 1e-9 {
+			continue
+		}
+		text := pi
+		switch rounded {
+		case 1:
+		case -1:
+			text = "-" + pi
+		default:
+			text = fmt.Sprintf("%d%s", int(rounded), pi)
+		}
+		if denominator > 1 {
+			text += fmt.Sprintf("/%d", denominator)
+		}
+		return text
+	}
+	return fmt.Sprintf("%.3g", p)
+}
@@ -0,0 +1,71 @@
+package diagram
+
+import (
+	"math"
+	"testing"
+
+	"github.com/benluxford/qe/circuit"
+)
+
+func TestSVG(t *testing.T) {
+	c := circuit.New(2).H(0).CNOT(0, 1).RZ(1, math.Pi/2).Measure(1, 0)
+	golden(t, "bell", SVG(c), `
+<svg xmlns="http://www.w3.org/2000/svg" width="268" height="140" viewBox="0 0 268 140" font-family="serif" font-size="14">
+<rect width="100%" height="100%" fill="white"/>
+<text x="37" y="30" text-anchor="end" dominant-baseline="middle">q0</text>
+<line x1="42" y1="30" x2="258" y2="30" stroke="black"/>
+<text x="37" y="70" text-anchor="end" dominant-baseline="middle">q1</text>
+<line x1="42" y1="70" x2="258" y2="70" stroke="black"/>
+<text x="37" y="110" text-anchor="end" dominant-baseline="middle">c</text>
+<line x1="42" y1="108" x2="258" y2="108" stroke="black"/>
+<line x1="42" y1="112" x2="258" y2="112" stroke="black"/>
+<rect x="52" y="15" width="30" height="30" fill="white" stroke="black"/>
+<text x="67" y="30" text-anchor="middle" dominant-baseline="middle">H</text>
+<line x1="107" y1="30" x2="107" y2="70" stroke="black"/>
+<circle cx="107" cy="30" r="5"/>
+<circle cx="107" cy="70" r="11" fill="white" stroke="black"/>
+<line x1="96" y1="70" x2="118" y2="70" stroke="black"/>
+<line x1="107" y1="59" x2="107" y2="81" stroke="black"/>
+<rect x="132" y="55" width="76" height="30" fill="white" stroke="black"/>
+<text x="170" y="70" text-anchor="middle" dominant-baseline="middle">RZ(π/2)</text>
+<line x1="231" y1="70" x2="231" y2="108" stroke="black"/>
+<line x1="235" y1="70" x2="235" y2="108" stroke="black"/>
+<path d="M 228 102 L 238 102 L 233 110 Z"/>
+<text x="233" y="124" text-anchor="middle" font-size="10">0</text>
+<rect x="218" y="55" width="30" height="30" fill="white" stroke="black"/>
+<path d="M 224 76 A 9 9 0 0 1 242 76" fill="none" stroke="black"/>
+<line x1="233" y1="76" x2="240" y2="62" stroke="black"/>
+</svg>
+`)
+}
+
+func TestSVGConditions(t *testing.T) {
+	c := circuit.New(2).H(0).Measure(0, 0).If([]int{0}, 1, circuit.New(2).Z(1))
+	golden(t, "conditions", SVG(c), `
+<svg xmlns="http://www.w3.org/2000/svg" width="182" height="140" viewBox="0 0 182 140" font-family="serif" font-size="14">
+<rect width="100%" height="100%" fill="white"/>
+<text x="37" y="30" text-anchor="end" dominant-baseline="middle">q0</text>
+<line x1="42" y1="30" x2="172" y2="30" stroke="black"/>
+<text x="37" y="70" text-anchor="end" dominant-baseline="middle">q1</text>
+<line x1="42" y1="70" x2="172" y2="70" stroke="black"/>
+<text x="37" y="110" text-anchor="end" dominant-baseline="middle">c</text>
+<line x1="42" y1="108" x2="172" y2="108" stroke="black"/>
+<line x1="42" y1="112" x2="172" y2="112" stroke="black"/>
+<rect x="52" y="15" width="30" height="30" fill="white" stroke="black"/>
+<text x="67" y="30" text-anchor="middle" dominant-baseline="middle">H</text>
+<line x1="105" y1="30" x2="105" y2="108" stroke="black"/>
+<line x1="109" y1="30" x2="109" y2="108" stroke="black"/>
+<path d="M 102 102 L 112 102 L 107 110 Z"/>
+<text x="107" y="124" text-anchor="middle" font-size="10">0</text>
+<rect x="92" y="15" width="30" height="30" fill="white" stroke="black"/>
+<path d="M 98 36 A 9 9 0 0 1 116 36" fill="none" stroke="black"/>
+<line x1="107" y1="36" x2="114" y2="22" stroke="black"/>
+<line x1="145" y1="70" x2="145" y2="110" stroke="black"/>
+<line x1="149" y1="70" x2="149" y2="110" stroke="black"/>
+<circle cx="147" cy="110" r="5"/>
+<text x="147" y="126" text-anchor="middle" font-size="10">c0=1</text>
+<rect x="132" y="55" width="30" height="30" fill="white" stroke="black"/>
+<text x="147" y="70" text-anchor="middle" dominant-baseline="middle">Z</text>
+</svg>
+`)
+}
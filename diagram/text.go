@@ -0,0 +1,363 @@
+package diagram
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/benluxford/qe/circuit"
+)
+
+// Text : Returns the Circuit drawn as text, three lines per qubit with the gates boxed on
+// their wire, ● for controls, ⊕ for controlled X targets, × for swaps and a meter box
+// joined by a double line to the classical wire for measurements. Lines longer than width
+// are wrapped, a width of zero never wraps
+func Text(c *circuit.Circuit, width int) string {
+	kinds := rows(c)
+	// a circuit without bits has no lines to draw
+	if len(kinds) == 0 {
+		return ""
+	}
+	names := wireNames(c)
+	prefix := 0
+	for _, name := range names {
+		if len([]rune(name)) > prefix {
+			prefix = len([]rune(name))
+		}
+	}
+
+	columns := [][][]rune{}
+	for _, layer := range layers(c) {
+		columns = append(columns, column(c, layer))
+	}
+
+	// split the columns into pages that fit the width, keeping at least one per page
+	pages := [][][][]rune{{}}
+	used := 0
+	for _, col := range columns {
+		w := len(col[0])
+		page := &pages[len(pages)-1]
+		if width > 0 && len(*page) > 0 && prefix+2+used+w > width {
+			pages = append(pages, [][][]rune{})
+			page = &pages[len(pages)-1]
+			used = 0
+		}
+		*page = append(*page, col)
+		used += w
+	}
+
+	var b strings.Builder
+	for p, page := range pages {
+		if p > 0 {
+			b.WriteString("\n")
+		}
+		for line, kind := range kinds {
+			label := ""
+			if kind == wire || kind == classical {
+				label = names[line/3]
+			}
+			b.WriteString(fmt.Sprintf("%*s", prefix, label))
+			// the ends of each line continue onto the neighbouring pages
+			switch {
+			case kind != wire && kind != classical:
+				b.WriteString(" ")
+			case p > 0:
+				b.WriteString("«")
+			default:
+				b.WriteRune(fill(kind))
+			}
+			for _, col := range page {
+				b.WriteString(string(col[line]))
+			}
+			switch {
+			case kind != wire && kind != classical:
+			case p < len(pages)-1:
+				b.WriteString("»")
+			default:
+				b.WriteRune(fill(kind))
+			}
+			b.WriteString("\n")
+		}
+	}
+	// trailing spaces from the box lines are trimmed
+	out := strings.Split(b.String(), "\n")
+	for i := range out {
+		out[i] = strings.TrimRight(out[i], " ")
+	}
+	return strings.Join(out, "\n")
+}
+
+// kind : What a line of the drawing shows
+type kind int
+
+const (
+	above kind = iota
+	wire
+	below
+	classical
+	index
+)
+
+// lineKind : Returns what a line of the drawing shows, qubit q owns lines 3q to 3q+2 and
+// the classical wire and its indices follow the last qubit
+func lineKind(c *circuit.Circuit, line int) kind {
+	if line >= 3*c.NumberOfBit() {
+		return classical + kind(line-3*c.NumberOfBit())
+	}
+	return kind(line % 3)
+}
+
+// fill : Returns the rune drawn on an empty stretch of a line
+func fill(k kind) rune {
+	switch k {
+	case wire:
+		return '─'
+	case classical:
+		return '═'
+	}
+	return ' '
+}
+
+// rows : Returns one entry per line of the drawing
+func rows(c *circuit.Circuit) []kind {
+	n := 3 * c.NumberOfBit()
+	if c.NumberOfClbit() > 0 {
+		n += 2
+	}
+	lines := make([]kind, n)
+	for i := range lines {
+		lines[i] = lineKind(c, i)
+	}
+	return lines
+}
+
+// wireNames : Returns the name written before each qubit and the classical wire, qubits in a
+// register are named after it
+func wireNames(c *circuit.Circuit) (names []string) {
+	for i := 0; i < c.NumberOfBit(); i++ {
+		if len(c.QuantumRegisters()) > 0 {
+			names = append(names, c.QubitName(i)+": ")
+			continue
+		}
+		names = append(names, fmt.Sprintf("q%d: ", i))
+	}
+	if c.NumberOfClbit() > 0 {
+		names = append(names, "c: ")
+	}
+	return
+}
+
+// span : Returns the first and last wire an operation is drawn across, measurements and
+// conditioned operations reach down to the classical wire below the last qubit. An operation
+// on no qubits, such as a barrier on an empty circuit, has the empty span 0 to -1
+func span(c *circuit.Circuit, o circuit.Op) (lo, hi int) {
+	qubits := o.Qubits()
+	if len(qubits) == 0 {
+		lo, hi = 0, -1
+	} else {
+		lo, hi = qubits[0], qubits[0]
+	}
+	for _, q := range qubits {
+		if q < lo {
+			lo = q
+		}
+		if q > hi {
+			hi = q
+		}
+	}
+	if len(o.Clbits) > 0 || o.Condition != nil {
+		hi = c.NumberOfBit()
+	}
+	return
+}
+
+// layers : Returns the operations packed into columns, an operation goes in the first
+// column after everything drawn across its wires
+func layers(c *circuit.Circuit) (packed [][]circuit.Op) {
+	level := make([]int, c.NumberOfBit()+1)
+	for _, o := range c.Ops() {
+		lo, hi := span(c, o)
+		l := 0
+		for q := lo; q <= hi; q++ {
+			if level[q] > l {
+				l = level[q]
+			}
+		}
+		for q := lo; q <= hi; q++ {
+			level[q] = l + 1
+		}
+		if l == len(packed) {
+			packed = append(packed, nil)
+		}
+		packed[l] = append(packed[l], o)
+	}
+	return
+}
+
+// label : Returns the text boxed for an operation, with any parameters, e.g. RZ(0.5)
+func label(o circuit.Op) string {
+	switch o.Name {
+	case "Measure":
+		return "M"
+	case "Reset":
+		return "|0>"
+	}
+	if len(o.Params) == 0 {
+		return o.Name
+	}
+	params := []string{}
+	for _, p := range o.Params {
+		params = append(params, fmt.Sprintf("%.3g", p))
+	}
+	return o.Name + "(" + strings.Join(params, ",") + ")"
+}
+
+// condition : Returns the test of a conditioned operation, e.g. c0=1 for a single classical
+// bit, out=3 for a whole register or c[0,2]=1 otherwise
+func condition(c *circuit.Circuit, o circuit.Op) string {
+	clbits, value := o.Condition.Clbits, o.Condition.Value
+	for _, r := range c.ClassicalRegisters() {
+		if fmt.Sprint(r.Bits()) == fmt.Sprint(clbits) {
+			return fmt.Sprintf("%s=%d", r.Name, value)
+		}
+	}
+	if len(clbits) == 1 {
+		return fmt.Sprintf("c%d=%d", clbits[0], value)
+	}
+	bits := []string{}
+	for _, b := range clbits {
+		bits = append(bits, fmt.Sprint(b))
+	}
+	return fmt.Sprintf("c[%s]=%d", strings.Join(bits, ","), value)
+}
+
+// symbol : Returns the single rune drawn for a target, or zero if it is boxed
+func symbol(o circuit.Op) rune {
+	switch {
+	case o.Name == "X" && len(o.Controls) > 0:
+		return '⊕'
+	case o.Name == "Swap":
+		return '×'
+	case o.Name == "Barrier":
+		return '░'
+	}
+	return 0
+}
+
+// column : Returns the lines of a single column of the drawing
+func column(c *circuit.Circuit, layer []circuit.Op) [][]rune {
+	kinds := rows(c)
+	// every box in the column shares the width of the widest
+	inner := 1
+	for _, o := range layer {
+		if o.Condition != nil && len([]rune(condition(c, o))) > inner {
+			inner = len([]rune(condition(c, o)))
+		}
+		if symbol(o) != 0 {
+			continue
+		}
+		l := len([]rune(label(o)))
+		if len(o.Targets) > 1 {
+			l += 1 + len(fmt.Sprint(len(o.Targets)-1))
+		}
+		if l+4 > inner {
+			inner = l + 4
+		}
+	}
+	w := inner + 2
+	centre := w / 2
+	lines := make([][]rune, len(kinds))
+	for i, k := range kinds {
+		lines[i] = []rune(strings.Repeat(string(fill(k)), w))
+	}
+
+	for _, o := range layer {
+		lo, hi := span(c, o)
+		// the vertical connector, double for the classical result of a measurement
+		if o.Name != "Barrier" {
+			first, last := 3*lo+2, 3*hi
+			if hi == c.NumberOfBit() {
+				last--
+			}
+			for line := first; line <= last; line++ {
+				double := len(o.Clbits) > 0 || o.Condition != nil
+				switch {
+				case kinds[line] == wire && double:
+					lines[line][centre] = '╫'
+				case kinds[line] == wire:
+					lines[line][centre] = '┼'
+				case double:
+					lines[line][centre] = '║'
+				default:
+					lines[line][centre] = '│'
+				}
+			}
+		}
+		for _, q := range o.Controls {
+			lines[3*q+1][centre] = '●'
+		}
+		for i, q := range o.Targets {
+			if s := symbol(o); s != 0 {
+				lines[3*q+1][centre] = s
+				if s == '░' {
+					lines[3*q][centre], lines[3*q+2][centre] = s, s
+				}
+				continue
+			}
+			text := label(o)
+			if len(o.Targets) > 1 {
+				text += fmt.Sprintf(":%d", i)
+			}
+			box(lines, q, centre, inner, text, q > lo, q < hi, len(o.Clbits) > 0 || o.Condition != nil)
+		}
+		// conditions are read off the classical wire, with the test written below
+		if o.Condition != nil {
+			wireLine := 3 * c.NumberOfBit()
+			lines[wireLine][centre] = '╩'
+			text := []rune(condition(c, o))
+			start := centre - (len(text)-1)/2
+			for i, r := range text {
+				if start+i >= 0 && start+i < w {
+					lines[wireLine+1][start+i] = r
+				}
+			}
+		}
+		// measurements land on the classical wire with the index of their bit below
+		for _, b := range o.Clbits {
+			wireLine := 3 * c.NumberOfBit()
+			lines[wireLine][centre] = '╩'
+			digits := []rune(fmt.Sprint(b))
+			start := centre - (len(digits)-1)/2
+			for i, r := range digits {
+				if start+i >= 0 && start+i < w {
+					lines[wireLine+1][start+i] = r
+				}
+			}
+		}
+	}
+	return lines
+}
+
+// box : Draws a labelled box of the given width on a qubit, joined to a connector coming
+// from above or going below
+func box(lines [][]rune, q, centre, width int, text string, up, down, measured bool) {
+	left := centre - width/2
+	top, middle, bottom := lines[3*q], lines[3*q+1], lines[3*q+2]
+	for i := 0; i < width; i++ {
+		top[left+i], bottom[left+i], middle[left+i] = '─', '─', ' '
+	}
+	top[left], top[left+width-1] = '┌', '┐'
+	bottom[left], bottom[left+width-1] = '└', '┘'
+	middle[left], middle[left+width-1] = '┤', '├'
+	runes := []rune(text)
+	start := left + (width-len(runes))/2
+	copy(middle[start:], runes)
+	if up {
+		top[centre] = '┴'
+	}
+	switch {
+	case measured:
+		bottom[centre] = '╥'
+	case down:
+		bottom[centre] = '┬'
+	}
+}
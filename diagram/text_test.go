@@ -0,0 +1,101 @@
+package diagram
+
+import (
+	"testing"
+
+	"github.com/benluxford/qe/circuit"
+)
+
+// golden : Fails unless the drawing matches the expected text, which starts on a new line
+func golden(t *testing.T, name, got, want string) {
+	t.Helper()
+	if "\n"+got != want {
+		t.Errorf("%s: got\n%s\nwant%s", name, got, want)
+	}
+}
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		name  string
+		c     *circuit.Circuit
+		width int
+		want  string
+	}{
+		{"bell", circuit.New(2).H(0).CNOT(0, 1).MeasureAll(), 0, `
+      ┌───┐     ┌───┐
+q0: ──┤ H ├──●──┤ M ├─────────
+      └───┘  │  └─╥─┘
+             │    ║    ┌───┐
+q1: ─────────⊕────╫────┤ M ├──
+                  ║    └─╥─┘
+ c: ══════════════╩══════╩════
+                  0      1
+`},
+		{"swap, barrier and Toffoli", circuit.New(3).RZ(0, 0.5).Swap(0, 2).Barrier().Toffoli(0, 1, 2).Measure(1, 0), 0, `
+      ┌─────────┐     ░
+q0: ──┤ RZ(0.5) ├──×──░──●─────────
+      └─────────┘  │  ░  │
+                   │  ░  │  ┌───┐
+q1: ───────────────┼──░──●──┤ M ├──
+                   │  ░  │  └─╥─┘
+                   │  ░  │    ║
+q2: ───────────────×──░──⊕────╫────
+                      ░       ║
+ c: ══════════════════════════╩════
+                              0
+`},
+		// the columns past the width continue on a second page
+		{"wrapped", circuit.New(2).H(0).T(1).CNOT(0, 1).S(0).H(1).X(0).Y(1), 24, `
+      ┌───┐     ┌───┐
+q0: ──┤ H ├──●──┤ S ├─»
+      └───┘  │  └───┘
+      ┌───┐  │  ┌───┐
+q1: ──┤ T ├──⊕──┤ H ├─»
+      └───┘     └───┘
+
+      ┌───┐
+q0: «─┤ X ├──
+      └───┘
+      ┌───┐
+q1: «─┤ Y ├──
+      └───┘
+`},
+		{"empty", circuit.New(0).Barrier(), 0, "\n"},
+	}
+	for _, tt := range tests {
+		golden(t, tt.name, Text(tt.c, tt.width), tt.want)
+	}
+}
+
+func TestTextRegisters(t *testing.T) {
+	c := circuit.New(0)
+	data := c.QuantumRegister("data", 2)
+	out := c.ClassicalRegister("out", 2)
+	c.H(data.At(0)).CNOT(data.At(0), data.At(1)).Measure(data.At(0), out.At(0)).Measure(data.At(1), out.At(1))
+	c.If(out.Bits(), 3, circuit.New(2).X(1))
+	golden(t, "registers", Text(c, 0), `
+           ┌───┐     ┌───┐
+data[0]: ──┤ H ├──●──┤ M ├────────────────
+           └───┘  │  └─╥─┘
+                  │    ║    ┌───┐  ┌───┐
+data[1]: ─────────⊕────╫────┤ M ├──┤ X ├──
+                       ║    └─╥─┘  └─╥─┘
+      c: ══════════════╩══════╩══════╩════
+                       0      1    out=3
+`)
+}
+
+func TestTextConditions(t *testing.T) {
+	c := circuit.New(2).H(0).Measure(0, 0).If([]int{0}, 1, circuit.New(2).Z(1)).
+		Measure(1, 1).If([]int{0, 1}, 2, circuit.New(2).X(0))
+	golden(t, "conditions", Text(c, 0), `
+      ┌───┐  ┌───┐                ┌──────┐
+q0: ──┤ H ├──┤ M ├────────────────┤  X   ├──
+      └───┘  └─╥─┘                └───╥──┘
+               ║    ┌───┐  ┌───┐      ║
+q1: ───────────╫────┤ Z ├──┤ M ├──────╫─────
+               ║    └─╥─┘  └─╥─┘      ║
+ c: ═══════════╩══════╩══════╩════════╩═════
+               0     c0=1    1     c[0,1]=2
+`)
+}
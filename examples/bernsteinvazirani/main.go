@@ -0,0 +1,55 @@
+// Bernstein-Vazirani finds the secret string s of f(x) = s.x mod 2 with a single query
+package main
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+
+	"github.com/benluxford/qe/circuit"
+	"github.com/benluxford/qe/diagram"
+	"github.com/benluxford/qe/examples/internal/expect"
+)
+
+const (
+	secret = "1011"
+	shots  = 1000
+)
+
+func main() {
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("ok")
+}
+
+// run : Builds and measures the circuit, and returns an error unless every shot reads the secret
+func run() error {
+	c := circuit.New(0)
+	x := c.QuantumRegister("x", len(secret))
+	y := c.QuantumRegister("y", 1)
+	out := c.ClassicalRegister("out", len(secret))
+	c.X(y.At(0)).H(y.At(0))
+	for _, q := range x.Bits() {
+		c.H(q)
+	}
+	// the oracle adds s.x to y, one CX for each one in the secret
+	for i, s := range secret {
+		if s == '1' {
+			c.CNOT(x.At(i), y.At(0))
+		}
+	}
+	for _, q := range x.Bits() {
+		c.H(q)
+	}
+	c.MeasureRegister(x, out)
+	fmt.Println(diagram.Text(c, 100))
+
+	counts, _ := c.Execute(nil, shots, rand.New(rand.NewSource(1))).Register("out")
+	expect.Print(counts, shots)
+	if err := expect.Distribution(counts, shots, map[string]float64{secret: 1}); err != nil {
+		return err
+	}
+	fmt.Printf("secret %s\n", secret)
+	return nil
+}
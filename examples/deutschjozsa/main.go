@@ -0,0 +1,94 @@
+// Deutsch-Jozsa decides with a single query whether a function on n bits is constant or
+// balanced, the input register measures all zeros exactly when it is constant
+package main
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"strings"
+
+	"github.com/benluxford/qe/circuit"
+	"github.com/benluxford/qe/diagram"
+	"github.com/benluxford/qe/examples/internal/expect"
+	"github.com/benluxford/qe/gate"
+)
+
+const (
+	bits  = 3
+	shots = 1000
+)
+
+func main() {
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("ok")
+}
+
+// run : Runs the algorithm on each oracle, and returns an error unless it tells the constant
+// functions from the balanced ones
+func run() error {
+	rng := rand.New(rand.NewSource(1))
+	// truth tables of f, entry x is f(x) with bit 0 the most significant bit of x
+	oracles := []struct {
+		name     string
+		table    string
+		constant bool
+	}{
+		{"constant zero", "00000000", true},
+		{"constant one", "11111111", true},
+		{"parity", "01101001", false},
+		{"balanced", "10110010", false},
+	}
+	zero := strings.Repeat("0", bits)
+	for _, o := range oracles {
+		c := circuit.New(0)
+		x := c.QuantumRegister("x", bits)
+		y := c.QuantumRegister("y", 1)
+		out := c.ClassicalRegister("out", bits)
+		// the output qubit starts in |->, so the oracle kicks f(x) back as a phase
+		c.X(y.At(0)).H(y.At(0))
+		for _, q := range x.Bits() {
+			c.H(q)
+		}
+		oracle(c, o.table, x, y)
+		for _, q := range x.Bits() {
+			c.H(q)
+		}
+		c.MeasureRegister(x, out)
+		if o.name == "balanced" {
+			fmt.Println(diagram.Text(c, 120))
+		}
+
+		counts, _ := c.Execute(nil, shots, rng).Register("out")
+		constant := counts[zero] == shots
+		fmt.Printf("%s (%s): constant %t\n", o.name, o.table, constant)
+		expect.Print(counts, shots)
+		if constant != o.constant {
+			return fmt.Errorf("%s was taken to be constant %t", o.name, constant)
+		}
+		if o.constant {
+			if err := expect.Distribution(counts, shots, map[string]float64{zero: 1}); err != nil {
+				return err
+			}
+		} else if counts[zero] > 0 {
+			return fmt.Errorf("%s measured %s %d times", o.name, zero, counts[zero])
+		}
+	}
+	return nil
+}
+
+// oracle : Appends |x>|y> -> |x>|y xor f(x)>, an X on y controlled on each x where f(x) is one
+func oracle(c *circuit.Circuit, table string, x, y circuit.Register) {
+	for input, f := range table {
+		if f != '1' {
+			continue
+		}
+		value := make([]int, x.Size)
+		for i := range value {
+			value[i] = input >> uint(x.Size-1-i) & 1
+		}
+		c.Controlled("X", gate.X(), x.Bits(), value, y.At(0))
+	}
+}
@@ -0,0 +1,42 @@
+// Error correction compares the logical error rate of each code in package qec with the
+// physical error rate of depolarizing noise on its qubits
+package main
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+
+	"github.com/benluxford/qe/qec"
+)
+
+const trials = 20000
+
+func main() {
+	rng := rand.New(rand.NewSource(1))
+	codes := []*qec.Code{qec.BitFlipCode(), qec.PhaseFlipCode(), qec.ShorCode(), qec.SteaneCode(), qec.SurfaceCode()}
+	physical := []float64{0.001, 0.005, 0.01, 0.05, 0.1}
+	points := [][]qec.Point{}
+	for _, c := range codes {
+		points = append(points, c.Sweep(qec.Depolarizing, physical, trials, rng))
+	}
+	fmt.Print(qec.Table(codes, points))
+
+	// the distance three codes correct any single error, so below threshold they beat a bare qubit;
+	// the bit and phase flip codes are distance one and do worse than a bare qubit here
+	for i, c := range codes {
+		p := points[i][2]
+		if c.Distance >= 3 && p.Logical >= p.Physical {
+			log.Fatalf("%v: logical %.4f is not below physical %.4f", c, p.Logical, p.Physical)
+		}
+	}
+	// a state vector run of the full circuits agrees with the Pauli frame rate
+	steane := codes[3]
+	simulated := steane.Simulate(qec.Depolarizing(0.05), 500, rng)
+	fmt.Printf("%v simulated: %v\n", steane, simulated)
+	if expected := points[3][3]; math.Abs(simulated.Logical-expected.Logical) > 5*simulated.Uncertainty()+0.01 {
+		log.Fatalf("simulated %.4f, expected %.4f", simulated.Logical, expected.Logical)
+	}
+	fmt.Println("ok")
+}
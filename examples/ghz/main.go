@@ -0,0 +1,49 @@
+// GHZ prepares (|0000> + |1111>) / sqrt(2), in which every qubit is entangled with the rest
+package main
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+
+	"github.com/benluxford/qe/circuit"
+	"github.com/benluxford/qe/diagram"
+	"github.com/benluxford/qe/examples/internal/expect"
+	"github.com/benluxford/qe/qubit"
+)
+
+const (
+	bits  = 4
+	shots = 10000
+)
+
+func main() {
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("ok")
+}
+
+// run : Prepares and measures the state, and returns an error unless every qubit carries one bit
+// of entanglement and the counts split evenly between 0000 and 1111
+func run() error {
+	c := circuit.New(bits).H(0)
+	for i := 0; i+1 < bits; i++ {
+		c.CNOT(i, i+1)
+	}
+	state := c.Run(qubit.Zero(bits))
+	c.MeasureAll()
+	fmt.Println(diagram.Text(c, 100))
+
+	fmt.Println(state)
+	// each qubit alone is maximally mixed, one bit of entanglement with the others
+	for i := 0; i < bits; i++ {
+		if entropy := state.EntanglementEntropy(i); entropy < 1-1e-9 {
+			return fmt.Errorf("qubit %d has %.3f bits of entanglement, expected 1", i, entropy)
+		}
+	}
+
+	counts := c.Execute(nil, shots, rand.New(rand.NewSource(1))).Counts()
+	expect.Print(counts, shots)
+	return expect.Distribution(counts, shots, map[string]float64{"0000": 0.5, "1111": 0.5})
+}
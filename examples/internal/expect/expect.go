@@ -0,0 +1,50 @@
+// Package expect checks the measured counts of the example programs against the outcome
+// distribution their algorithm predicts
+package expect
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// sigmas : How many standard deviations a count may stray from its expected value
+const sigmas = 5
+
+// Distribution : Returns an error if any outcome was measured that is not expected, or if the
+// count of an expected outcome is more than five standard deviations from shots * p
+func Distribution(counts map[string]int, shots int, expected map[string]float64) error {
+	for outcome, n := range counts {
+		if expected[outcome] == 0 {
+			return fmt.Errorf("expect: measured %s %d times, it should never occur", outcome, n)
+		}
+	}
+	outcomes := []string{}
+	for outcome := range expected {
+		outcomes = append(outcomes, outcome)
+	}
+	sort.Strings(outcomes)
+	for _, outcome := range outcomes {
+		p := expected[outcome]
+		mean := float64(shots) * p
+		deviation := math.Sqrt(float64(shots) * p * (1 - p))
+		if math.Abs(float64(counts[outcome])-mean) > sigmas*deviation+1e-9 {
+			return fmt.Errorf("expect: measured %s %d times, expected %.0f ± %.0f", outcome, counts[outcome], mean, sigmas*deviation)
+		}
+	}
+	return nil
+}
+
+// Print : Writes the counts in order of outcome with a bar for each
+func Print(counts map[string]int, shots int) {
+	outcomes := []string{}
+	for outcome := range counts {
+		outcomes = append(outcomes, outcome)
+	}
+	sort.Strings(outcomes)
+	for _, outcome := range outcomes {
+		bar := int(math.Round(40 * float64(counts[outcome]) / float64(shots)))
+		fmt.Printf("%s %6d %s\n", outcome, counts[outcome], strings.Repeat("█", bar))
+	}
+}
@@ -0,0 +1,107 @@
+// Simon's algorithm finds the hidden period s of a two to one function with f(x) = f(x xor s).
+// Each run measures a random y with y.s = 0 mod 2, and a few of them pin down s
+package main
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"strings"
+
+	"github.com/benluxford/qe/circuit"
+	"github.com/benluxford/qe/diagram"
+	"github.com/benluxford/qe/examples/internal/expect"
+)
+
+const (
+	secret = "110"
+	shots  = 10000
+)
+
+func main() {
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("ok")
+}
+
+// run : Builds and measures the circuit, and returns an error unless the measured strings are
+// spread evenly over those orthogonal to the secret and pin it down
+func run() error {
+	n := len(secret)
+	c := circuit.New(0)
+	x := c.QuantumRegister("x", n)
+	y := c.QuantumRegister("y", n)
+	out := c.ClassicalRegister("out", n)
+	for _, q := range x.Bits() {
+		c.H(q)
+	}
+	oracle(c, x, y)
+	for _, q := range x.Bits() {
+		c.H(q)
+	}
+	c.MeasureRegister(x, out)
+	fmt.Println(diagram.Text(c, 100))
+
+	counts, _ := c.Execute(nil, shots, rand.New(rand.NewSource(1))).Register("out")
+	expect.Print(counts, shots)
+	// every y orthogonal to the secret is equally likely
+	expected := map[string]float64{}
+	for v := 0; v < 1<<uint(n); v++ {
+		if dot(v, parse(secret)) == 0 {
+			expected[fmt.Sprintf("%0*b", n, v)] = 1 / float64(int(1)<<uint(n-1))
+		}
+	}
+	if err := expect.Distribution(counts, shots, expected); err != nil {
+		return err
+	}
+
+	// the secret is the only non zero string orthogonal to everything measured
+	candidates := []string{}
+	for s := 1; s < 1<<uint(n); s++ {
+		orthogonal := true
+		for measured := range counts {
+			if dot(parse(measured), s) != 0 {
+				orthogonal = false
+			}
+		}
+		if orthogonal {
+			candidates = append(candidates, fmt.Sprintf("%0*b", n, s))
+		}
+	}
+	fmt.Printf("secret %s\n", strings.Join(candidates, " or "))
+	if len(candidates) != 1 || candidates[0] != secret {
+		return fmt.Errorf("found %v, expected %s", candidates, secret)
+	}
+	return nil
+}
+
+// oracle : Appends |x>|0> -> |x>|f(x)> with f(x) = f(x xor s), copying x to y then adding s
+// whenever x has a one at the first one of s, so x and x xor s land on the same output
+func oracle(c *circuit.Circuit, x, y circuit.Register) {
+	for i := 0; i < x.Size; i++ {
+		c.CNOT(x.At(i), y.At(i))
+	}
+	first := strings.Index(secret, "1")
+	for i, s := range secret {
+		if s == '1' {
+			c.CNOT(x.At(first), y.At(i))
+		}
+	}
+}
+
+// parse : Returns the value of a bit string, bit 0 on the left
+func parse(bits string) (value int) {
+	for _, b := range bits {
+		value = value<<1 | int(b-'0')
+	}
+	return
+}
+
+// dot : Returns the parity of the bitwise and of two values
+func dot(a, b int) (parity int) {
+	for v := a & b; v > 0; v >>= 1 {
+		parity ^= v & 1
+	}
+	return
+}
@@ -0,0 +1,51 @@
+// Superdense coding sends two classical bits by sending one qubit of a shared Bell pair
+package main
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+
+	"github.com/benluxford/qe/circuit"
+	"github.com/benluxford/qe/diagram"
+	"github.com/benluxford/qe/examples/internal/expect"
+)
+
+const shots = 1000
+
+func main() {
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("ok")
+}
+
+// run : Sends each two bit message, and returns an error unless bob decodes it every shot
+func run() error {
+	rng := rand.New(rand.NewSource(1))
+	for _, message := range []string{"00", "01", "10", "11"} {
+		c := circuit.New(2)
+		// share a Bell pair, alice holds qubit 0 and bob qubit 1
+		c.H(0).CNOT(0, 1)
+		// alice encodes the message on her qubit alone
+		if message[1] == '1' {
+			c.X(0)
+		}
+		if message[0] == '1' {
+			c.Z(0)
+		}
+		// bob decodes with a Bell measurement
+		c.CNOT(0, 1).H(0).MeasureAll()
+		if message == "11" {
+			fmt.Println(diagram.Text(c, 100))
+		}
+
+		counts := c.Execute(nil, shots, rng).Counts()
+		fmt.Printf("sent %s:\n", message)
+		expect.Print(counts, shots)
+		if err := expect.Distribution(counts, shots, map[string]float64{message: 1}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
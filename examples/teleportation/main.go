@@ -0,0 +1,63 @@
+// Teleportation sends the state of one qubit to another using a shared Bell pair, two
+// measured classical bits and corrections conditioned on them
+package main
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"math/cmplx"
+	"math/rand"
+
+	"github.com/benluxford/qe/circuit"
+	"github.com/benluxford/qe/diagram"
+	"github.com/benluxford/qe/examples/internal/expect"
+	"github.com/benluxford/qe/qubit"
+)
+
+const shots = 10000
+
+func main() {
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("ok")
+}
+
+// run : Teleports the state, and returns an error unless bob measures it with the probabilities
+// of the state sent and alice's results are uniform
+func run() error {
+	// the state to send, cos(theta/2)|0> + e^(i phi) sin(theta/2)|1>
+	theta, phi := 1.1, 0.7
+	psi := qubit.New(complex(math.Cos(theta/2), 0), cmplx.Rect(math.Sin(theta/2), phi))
+	input := qubit.TensorProduct(psi, qubit.Zero(2))
+
+	c := circuit.New(0)
+	alice := c.QuantumRegister("alice", 2)
+	bob := c.QuantumRegister("bob", 1)
+	m := c.ClassicalRegister("m", 2)
+	out := c.ClassicalRegister("out", 1)
+	// share a Bell pair between alice[1] and bob
+	c.H(alice.At(1)).CNOT(alice.At(1), bob.At(0))
+	// alice measures her qubits in the Bell basis
+	c.CNOT(alice.At(0), alice.At(1)).H(alice.At(0))
+	c.MeasureRegister(alice, m)
+	// bob corrects his qubit with the two classical bits
+	c.If([]int{m.At(1)}, 1, circuit.New(3).X(bob.At(0)))
+	c.If([]int{m.At(0)}, 1, circuit.New(3).Z(bob.At(0)))
+	c.Measure(bob.At(0), out.At(0))
+	fmt.Println(diagram.Text(c, 100))
+
+	result := c.Execute(input, shots, rand.New(rand.NewSource(1)))
+	received, _ := result.Register("out")
+	fmt.Printf("sent %v\n", psi)
+	expect.Print(received, shots)
+	// bob measures one with the probability of the state sent
+	one := math.Pow(math.Sin(theta/2), 2)
+	if err := expect.Distribution(received, shots, map[string]float64{"0": 1 - one, "1": one}); err != nil {
+		return err
+	}
+	// every pair of alice's results is equally likely
+	measured, _ := result.Register("m")
+	return expect.Distribution(measured, shots, map[string]float64{"00": 0.25, "01": 0.25, "10": 0.25, "11": 0.25})
+}
@@ -1,10 +1,11 @@
+// Package gate builds gate matrices. Bits are numbered as in package qubit: bit 0 is the most
+// significant bit of a row or column index and the leftmost factor of a tensor product, so
+// CNOT(2, 0, 1) flips the low bit when the high bit is one.
 package gate
 
 import (
-	"fmt"
 	"math"
 	"math/cmplx"
-	"strconv"
 
 	"github.com/benluxford/qe/matrix"
 )
@@ -91,148 +92,133 @@ func T(bit ...int) matrix.Matrix {
 	return matrix.TensorProductN(m, bit...)
 }
 
-func ControlledR(bit int, c []int, t, k int) matrix.Matrix {
-	m := I([]int{bit}...)
-	dim := len(m)
+func SDagger(bit ...int) matrix.Matrix {
+	m := make(matrix.Matrix, 2)
+	m[0] = []complex128{1, 0}
+	m[1] = []complex128{0, -1i}
+	return matrix.TensorProductN(m, bit...)
+}
 
-	p := 2 * math.Pi / math.Pow(2, float64(k))
-	e := cmplx.Exp(complex(0, p))
+func TDagger(bit ...int) matrix.Matrix {
+	m := make(matrix.Matrix, 2)
+	v := cmplx.Exp(complex(0, -1) * math.Pi / 4)
+	m[0] = []complex128{1, 0}
+	m[1] = []complex128{0, v}
+	return matrix.TensorProductN(m, bit...)
+}
 
-	f := "%0" + strconv.Itoa(bit) + "s"
-	for i := 0; i < dim; i++ {
-		s := fmt.Sprintf(f, strconv.FormatInt(int64(i), 2))
-		bits := []rune(s)
+func SX(bit ...int) matrix.Matrix {
+	m := make(matrix.Matrix, 2)
+	m[0] = []complex128{(1 + 1i) / 2, (1 - 1i) / 2}
+	m[1] = []complex128{(1 - 1i) / 2, (1 + 1i) / 2}
+	return matrix.TensorProductN(m, bit...)
+}
 
-		// Apply R(k)
-		apply := true
-		for i := range c {
-			if bits[c[i]] == '0' {
-				apply = false
-				break
-			}
-		}
+func U3(theta, phi, lambda float64) matrix.Matrix {
+	m := make(matrix.Matrix, 2)
+	c, s := complex(math.Cos(theta/2), 0), complex(math.Sin(theta/2), 0)
+	m[0] = []complex128{c, -cmplx.Exp(complex(0, lambda)) * s}
+	m[1] = []complex128{cmplx.Exp(complex(0, phi)) * s, cmplx.Exp(complex(0, phi+lambda)) * c}
+	return m
+}
 
-		if apply && bits[t] == '1' {
-			m[i][i] = e * m[i][i]
-		}
-	}
+func P(phi float64) matrix.Matrix {
+	m := make(matrix.Matrix, 2)
+	m[0] = []complex128{1, 0}
+	m[1] = []complex128{0, cmplx.Exp(complex(0, phi))}
+	return m
+}
+
+func RX(theta float64) matrix.Matrix {
+	m := make(matrix.Matrix, 2)
+	c, s := complex(math.Cos(theta/2), 0), complex(0, -math.Sin(theta/2))
+	m[0] = []complex128{c, s}
+	m[1] = []complex128{s, c}
+	return m
+}
 
+func RY(theta float64) matrix.Matrix {
+	m := make(matrix.Matrix, 2)
+	c, s := complex(math.Cos(theta/2), 0), complex(math.Sin(theta/2), 0)
+	m[0] = []complex128{c, -s}
+	m[1] = []complex128{s, c}
 	return m
 }
 
-func CR(bit, c, t, k int) matrix.Matrix {
-	return ControlledR(bit, []int{c}, t, k)
+func RZ(theta float64) matrix.Matrix {
+	m := make(matrix.Matrix, 2)
+	m[0] = []complex128{cmplx.Exp(complex(0, -theta/2)), 0}
+	m[1] = []complex128{0, cmplx.Exp(complex(0, theta/2))}
+	return m
 }
 
-func ControlledNot(bit int, c []int, t int) matrix.Matrix {
-	m := I([]int{bit}...)
-	dim := len(m)
+func Controlled(bit int, u matrix.Matrix, c, value []int, t ...int) matrix.Matrix {
+	dim := 1 << uint(bit)
+	m := make(matrix.Matrix, dim)
+	for i := range m {
+		m[i] = make([]complex128, dim)
+	}
 
-	index := []int64{}
-	f := "%0" + strconv.Itoa(bit) + "s"
-	for i := 0; i < dim; i++ {
-		s := fmt.Sprintf(f, strconv.FormatInt(int64(i), 2))
-		bits := []rune(s)
+	// Bit q of an index, bit 0 is the most significant
+	at := func(i, q int) int {
+		return (i >> uint(bit-1-q)) & 1
+	}
 
-		// Apply X
+	for j := 0; j < dim; j++ {
+		// Apply u when every control holds its value, one by default
 		apply := true
 		for i := range c {
-			if bits[c[i]] == '0' {
+			v := 1
+			if value != nil {
+				v = value[i]
+			}
+			if at(j, c[i]) != v {
 				apply = false
 				break
 			}
 		}
 
-		if apply {
-			if bits[t] == '0' {
-				bits[t] = '1'
-			} else {
-				bits[t] = '0'
-			}
+		if !apply {
+			m[j][j] = 1
+			continue
 		}
 
-		v, err := strconv.ParseInt(string(bits), 2, 0)
-		if err != nil {
-			panic(err)
+		// Column sub of u spreads over the rows sharing every other bit with j
+		sub := 0
+		for _, q := range t {
+			sub = sub<<1 | at(j, q)
+		}
+		for r := range u {
+			row := j
+			for k, q := range t {
+				shift := uint(bit - 1 - q)
+				row = row&^(1<<shift) | (r>>uint(len(t)-1-k)&1)<<shift
+			}
+			m[row][j] = u[r][sub]
 		}
-
-		index = append(index, v)
 	}
 
-	cnot := make(matrix.Matrix, dim)
-	for i, ii := range index {
-		cnot[i] = m[ii]
-	}
+	return m
+}
 
-	return cnot
+func CR(bit, c, t, k int) matrix.Matrix {
+	return Controlled(bit, R(k), []int{c}, nil, t)
 }
 
 func Toffoli() matrix.Matrix {
-	return ControlledNot(3, []int{0, 1}, 2)
+	return Controlled(3, X(), []int{0, 1}, nil, 2)
 }
 
 func CNOT(bit, c, t int) matrix.Matrix {
-	return ControlledNot(bit, []int{c}, t)
-}
-
-func ControlledZ(bit int, c []int, t int) matrix.Matrix {
-	m := I([]int{bit}...)
-	dim := len(m)
-
-	f := "%0" + strconv.Itoa(bit) + "s"
-	for i := 0; i < dim; i++ {
-		s := fmt.Sprintf(f, strconv.FormatInt(int64(i), 2))
-		bits := []rune(s)
-
-		// Apply Z
-		apply := true
-		for i := range c {
-			if bits[c[i]] == '0' {
-				apply = false
-				break
-			}
-		}
-
-		if apply && bits[t] == '1' {
-			m[i][i] = complex(-1, 0) * m[i][i]
-		}
-	}
-
-	return m
+	return Controlled(bit, X(), []int{c}, nil, t)
 }
 
 func CZ(bit, c, t int) matrix.Matrix {
-	return ControlledZ(bit, []int{c}, t)
-}
-
-func ControlledS(bit int, c []int, t int) matrix.Matrix {
-	m := I([]int{bit}...)
-	dim := len(m)
-
-	f := "%0" + strconv.Itoa(bit) + "s"
-	for i := 0; i < dim; i++ {
-		s := fmt.Sprintf(f, strconv.FormatInt(int64(i), 2))
-		bits := []rune(s)
-
-		// Apply S
-		apply := true
-		for i := range c {
-			if bits[c[i]] == '0' {
-				apply = false
-				break
-			}
-		}
-
-		if apply && bits[t] == '1' {
-			m[i][i] = 1i * m[i][i]
-		}
-	}
-
-	return m
+	return Controlled(bit, Z(), []int{c}, nil, t)
 }
 
 func CS(bit, c, t int) matrix.Matrix {
-	return ControlledS(bit, []int{c}, t)
+	return Controlled(bit, S(), []int{c}, nil, t)
 }
 
 func Swap(bit, c, t int) matrix.Matrix {
@@ -0,0 +1,207 @@
+package gate
+
+import (
+	"math"
+	"math/cmplx"
+	"strconv"
+	"testing"
+
+	"github.com/benluxford/qe/matrix"
+	"github.com/benluxford/qe/qubit"
+)
+
+// ket : Returns the big endian basis state written as a ket, e.g. "011"
+func ket(bits string) *qubit.Qubit {
+	index, _ := strconv.ParseInt(bits, 2, 64)
+	amplitudes := make([]complex128, 1<<uint(len(bits)))
+	amplitudes[index] = 1
+	return qubit.New(amplitudes...)
+}
+
+// basis : Returns the basis state with the given index among n bits in the Order
+func basis(order qubit.Order, index, n int) *qubit.Qubit {
+	amplitudes := make([]complex128, 1<<uint(n))
+	amplitudes[index] = 1
+	return qubit.FromAmplitudes(order, amplitudes...)
+}
+
+func TestMultiQubitGatesOnBasisStates(t *testing.T) {
+	i := complex(0, 1)
+	// kets are written big endian, |b0 b1 ... bn-1>, and each maps to phase times a ket
+	tests := []struct {
+		name  string
+		gate  matrix.Matrix
+		in    string
+		out   string
+		phase complex128
+	}{
+		{"CNOT control 0 off", CNOT(2, 0, 1), "01", "01", 1},
+		{"CNOT control 0 on", CNOT(2, 0, 1), "10", "11", 1},
+		{"CNOT control 0 on target set", CNOT(2, 0, 1), "11", "10", 1},
+		{"CNOT control 1", CNOT(2, 1, 0), "01", "11", 1},
+		{"CNOT control 1 off", CNOT(2, 1, 0), "10", "10", 1},
+		{"CNOT across a bit", CNOT(3, 0, 2), "100", "101", 1},
+		{"CNOT upwards across a bit", CNOT(3, 2, 0), "001", "101", 1},
+		{"CZ both set", CZ(2, 0, 1), "11", "11", -1},
+		{"CZ one set", CZ(2, 0, 1), "10", "10", 1},
+		{"CZ one set", CZ(2, 1, 0), "01", "01", 1},
+		{"CS both set", CS(2, 0, 1), "11", "11", i},
+		{"CS control only", CS(2, 0, 1), "10", "10", 1},
+		{"CS target only", CS(2, 0, 1), "01", "01", 1},
+		{"CR k=3 both set", CR(2, 0, 1, 3), "11", "11", cmplx.Exp(complex(0, math.Pi/4))},
+		{"CR k=3 control only", CR(2, 1, 0, 3), "01", "01", 1},
+		{"CR across a bit", CR(3, 2, 0, 2), "101", "101", i},
+		{"Swap", Swap(2, 0, 1), "10", "01", 1},
+		{"Swap equal bits", Swap(2, 0, 1), "11", "11", 1},
+		{"Swap across a bit", Swap(3, 0, 2), "110", "011", 1},
+		{"Toffoli both set", Toffoli(), "110", "111", 1},
+		{"Toffoli both set target set", Toffoli(), "111", "110", 1},
+		{"Toffoli one set", Toffoli(), "101", "101", 1},
+		{"Toffoli other set", Toffoli(), "010", "010", 1},
+		{"Fredkin on", Fredkin(), "110", "101", 1},
+		{"Fredkin on reversed", Fredkin(), "101", "110", 1},
+		{"Fredkin off", Fredkin(), "010", "010", 1},
+		{"Fredkin on equal", Fredkin(), "111", "111", 1},
+	}
+	for _, tt := range tests {
+		got := ket(tt.in).Apply(tt.gate)
+		want := ket(tt.out)
+		for k, a := range want.Amplitude() {
+			if cmplx.Abs(got.Amplitude()[k]-tt.phase*a) > 1e-9 {
+				t.Errorf("%s: |%s> gave %v, want %v |%s>", tt.name, tt.in, got.Amplitude(), tt.phase, tt.out)
+				break
+			}
+		}
+	}
+}
+
+func TestMultiQubitGatesLittleEndian(t *testing.T) {
+	// indices are little endian, bit 0 is the least significant, so index 1 of three bits
+	// has only bit 0 set and index 4 only bit 2
+	tests := []struct {
+		name    string
+		gate    matrix.Matrix
+		n       int
+		in, out int
+		phase   complex128
+	}{
+		{"CNOT control 0 on", CNOT(2, 0, 1), 2, 1, 3, 1},
+		{"CNOT control 0 off", CNOT(2, 0, 1), 2, 2, 2, 1},
+		{"CNOT control 1 on", CNOT(2, 1, 0), 2, 2, 3, 1},
+		{"CNOT control 1 off", CNOT(2, 1, 0), 2, 1, 1, 1},
+		{"CNOT of three", CNOT(3, 0, 1), 3, 1, 3, 1},
+		{"CNOT of three upwards", CNOT(3, 2, 0), 3, 4, 5, 1},
+		{"CZ one set", CZ(2, 0, 1), 2, 1, 1, 1},
+		{"CS both set", CS(2, 0, 1), 2, 3, 3, complex(0, 1)},
+		{"CR k=3 both set", CR(2, 0, 1, 3), 2, 3, 3, cmplx.Exp(complex(0, math.Pi/4))},
+		{"Swap", Swap(3, 0, 1), 3, 1, 2, 1},
+		{"Swap across a bit", Swap(3, 0, 2), 3, 3, 6, 1},
+		{"Toffoli both set", Toffoli(), 3, 3, 7, 1},
+		{"Toffoli both set target set", Toffoli(), 3, 7, 3, 1},
+		{"Toffoli one set", Toffoli(), 3, 5, 5, 1},
+		{"Fredkin on", Fredkin(), 3, 3, 5, 1},
+		{"Fredkin off", Fredkin(), 3, 6, 6, 1},
+	}
+	for _, tt := range tests {
+		got := basis(qubit.LittleEndian, tt.in, tt.n).Apply(tt.gate).AmplitudeIn(qubit.LittleEndian)
+		for k, a := range got {
+			want := complex128(0)
+			if k == tt.out {
+				want = tt.phase
+			}
+			if cmplx.Abs(a-want) > 1e-9 {
+				t.Errorf("%s: %d gave %v, want %v at %d", tt.name, tt.in, got, tt.phase, tt.out)
+				break
+			}
+		}
+	}
+}
+
+func TestQFTOnBasisStates(t *testing.T) {
+	// QFT |x> has amplitude e^(2 pi i x y / N) / sqrt(N) on |y>, x and y read big endian
+	const n = 3
+	for x := 0; x < 1<<n; x++ {
+		bits := strconv.FormatInt(int64(x|1<<n), 2)[1:]
+		got := ket(bits).Apply(QFT(n)).Amplitude()
+		for y := 0; y < 1<<n; y++ {
+			want := cmplx.Exp(complex(0, 2*math.Pi*float64(x*y)/(1<<n))) / complex(math.Sqrt(1<<n), 0)
+			if cmplx.Abs(got[y]-want) > 1e-9 {
+				t.Errorf("QFT |%s> amplitude of %d is %v, want %v", bits, y, got[y], want)
+			}
+		}
+	}
+}
+
+func TestQFTLittleEndian(t *testing.T) {
+	// little endian index 1 is big endian |100>, x = 4, with amplitudes (-1)^y, and index 4 is
+	// |001>, x = 1, with amplitudes e^(i pi y / 4); the little endian index j holds y = j reversed
+	tests := []struct {
+		in    int
+		turns []int
+	}{
+		{1, []int{0, 0, 0, 0, 4, 4, 4, 4}},
+		{4, []int{0, 4, 2, 6, 1, 5, 3, 7}},
+		{0, []int{0, 0, 0, 0, 0, 0, 0, 0}},
+	}
+	for _, tt := range tests {
+		got := basis(qubit.LittleEndian, tt.in, 3).Apply(QFT(3)).AmplitudeIn(qubit.LittleEndian)
+		for j, k := range tt.turns {
+			want := cmplx.Exp(complex(0, math.Pi*float64(k)/4)) / complex(math.Sqrt(8), 0)
+			if cmplx.Abs(got[j]-want) > 1e-9 {
+				t.Errorf("QFT of %d: amplitude of %d is %v, want %v", tt.in, j, got[j], want)
+			}
+		}
+	}
+}
+
+func TestControlled(t *testing.T) {
+	// permutation : Returns the dim x dim identity with the listed pairs of basis states swapped
+	permutation := func(dim int, swaps ...[2]int) matrix.Matrix {
+		m := make(matrix.Matrix, dim)
+		for i := range m {
+			m[i] = make([]complex128, dim)
+			m[i][i] = 1
+		}
+		for _, s := range swaps {
+			a, b := s[0], s[1]
+			m[a][a], m[b][b], m[a][b], m[b][a] = 0, 0, 1, 1
+		}
+		return m
+	}
+	tests := []struct {
+		name string
+		got  matrix.Matrix
+		want matrix.Matrix
+	}{
+		{"CX", Controlled(2, X(), []int{0}, nil, 1), permutation(4, [2]int{2, 3})},
+		{"CX on zero", Controlled(2, X(), []int{0}, []int{0}, 1), permutation(4, [2]int{0, 1})},
+		{"CX upwards", Controlled(2, X(), []int{1}, nil, 0), permutation(4, [2]int{1, 3})},
+		{"CX upwards on zero", Controlled(2, X(), []int{1}, []int{0}, 0), permutation(4, [2]int{0, 2})},
+		// X on bit 1 when bit 0 is one and bit 2 is zero, |100> and |110>
+		{"mixed controls", Controlled(3, X(), []int{0, 2}, []int{1, 0}, 1), permutation(8, [2]int{4, 6})},
+		// a CX from bit 2 to bit 0 when bit 1 is zero, |001> and |101>
+		{"two targets", Controlled(3, CNOT(2, 0, 1), []int{1}, []int{0}, 2, 0), permutation(8, [2]int{1, 5})},
+		{"no controls", Controlled(2, X(), nil, nil, 1), permutation(4, [2]int{0, 1}, [2]int{2, 3})},
+		{"controlled Z on zero", Controlled(2, Z(), []int{0}, []int{0}, 1), matrix.Matrix{{1, 0, 0, 0}, {0, -1, 0, 0}, {0, 0, 1, 0}, {0, 0, 0, 1}}},
+	}
+	for _, tt := range tests {
+		if !tt.got.Equals(tt.want, 1e-12) {
+			t.Errorf("%s: got %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+
+	// a control on zero is a control on one between two X
+	u := U(0.3, 1.1, -0.7, 2.2)
+	for _, value := range [][]int{{0, 0}, {0, 1}, {1, 0}, {1, 1}} {
+		flip := I(4)
+		for i, c := range []int{3, 0} {
+			if value[i] == 0 {
+				flip = flip.Apply(Controlled(4, X(), nil, nil, c))
+			}
+		}
+		want := flip.Apply(Controlled(4, u, []int{3, 0}, nil, 1)).Apply(flip)
+		if got := Controlled(4, u, []int{3, 0}, value, 1); !got.Equals(want, 1e-12) {
+			t.Errorf("controls on %v: got %v, want %v", value, got, want)
+		}
+	}
+}
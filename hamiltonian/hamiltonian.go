@@ -0,0 +1,104 @@
+package hamiltonian
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/benluxford/qe/gate"
+	"github.com/benluxford/qe/matrix"
+	"github.com/benluxford/qe/qubit"
+)
+
+// Term : A real coefficient times a Pauli string, Pauli[i] is one of I, X, Y or Z acting on bit i
+type Term struct {
+	Coefficient float64
+	Pauli       string
+}
+
+// Hamiltonian : A hermitian sum of Pauli terms on a fixed number of bits
+type Hamiltonian struct {
+	bit   int
+	terms []Term
+}
+
+// New : Returns a pointer to a new Hamiltonian on the given number of bits, or an error if a
+// Pauli string has the wrong length or a letter other than I, X, Y and Z
+func New(bit int, terms ...Term) (*Hamiltonian, error) {
+	for _, t := range terms {
+		if len(t.Pauli) != bit {
+			return nil, fmt.Errorf("hamiltonian: %q does not act on %d bits", t.Pauli, bit)
+		}
+		if strings.Trim(t.Pauli, "IXYZ") != "" {
+			return nil, fmt.Errorf("hamiltonian: %q is not a Pauli string", t.Pauli)
+		}
+	}
+	return &Hamiltonian{bit, append([]Term{}, terms...)}, nil
+}
+
+// NumberOfBit : Returns the number of bits the Hamiltonian acts on
+func (h *Hamiltonian) NumberOfBit() int {
+	return h.bit
+}
+
+// Terms : Returns the terms of the Hamiltonian in order
+func (h *Hamiltonian) Terms() []Term {
+	return h.terms
+}
+
+// String : Returns the Hamiltonian as e.g. "0.5 XX + -1 ZI"
+func (h *Hamiltonian) String() string {
+	terms := []string{}
+	for _, t := range h.terms {
+		terms = append(terms, fmt.Sprintf("%g %s", t.Coefficient, t.Pauli))
+	}
+	return strings.Join(terms, " + ")
+}
+
+// Matrix : Returns the matrix of the Hamiltonian, the sum of each term's tensor product
+func (h *Hamiltonian) Matrix() matrix.Matrix {
+	dim := 1 << uint(h.bit)
+	sum := make(matrix.Matrix, dim)
+	for i := range sum {
+		sum[i] = make([]complex128, dim)
+	}
+	for _, t := range h.terms {
+		factors := []matrix.Matrix{}
+		for _, p := range t.Pauli {
+			factors = append(factors, pauli(p))
+		}
+		sum = sum.Add(matrix.TensorProduct(factors...).Multiply(complex(t.Coefficient, 0)))
+	}
+	return sum
+}
+
+// Exact : Returns the time evolution operator exp(-iHt) as a matrix exponential
+func (h *Hamiltonian) Exact(t float64) matrix.Matrix {
+	return h.Matrix().Multiply(complex(0, -t)).Exp()
+}
+
+// Evolve : Returns a new Qubit holding exp(-iHt) applied to the input
+func (h *Hamiltonian) Evolve(input *qubit.Qubit, t float64) *qubit.Qubit {
+	return input.Clone().Apply(h.Exact(t))
+}
+
+// Norm : Returns the sum of the magnitudes of the coefficients, which bounds the spectral norm
+func (h *Hamiltonian) Norm() (norm float64) {
+	for _, t := range h.terms {
+		norm += math.Abs(t.Coefficient)
+	}
+	return
+}
+
+// pauli : Returns the single bit matrix of a Pauli letter
+func pauli(p rune) matrix.Matrix {
+	switch p {
+	case 'X':
+		return gate.X()
+	case 'Y':
+		return gate.Y()
+	case 'Z':
+		return gate.Z()
+	}
+	return gate.I()
+}
@@ -0,0 +1,210 @@
+package hamiltonian
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"github.com/benluxford/qe/matrix"
+	"github.com/benluxford/qe/qubit"
+)
+
+// mixed : Returns a Hamiltonian on three bits whose terms do not commute
+func mixed(t *testing.T) *Hamiltonian {
+	h, err := New(3, Term{1, "XXI"}, Term{0.7, "IYY"}, Term{-0.5, "ZIZ"}, Term{0.3, "XIY"}, Term{0.4, "IZI"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name  string
+		terms []Term
+		ok    bool
+	}{
+		{"valid", []Term{{1, "XY"}, {-0.5, "ZI"}}, true},
+		{"no terms", nil, true},
+		{"too short", []Term{{1, "X"}}, false},
+		{"too long", []Term{{1, "XYZ"}}, false},
+		{"not a Pauli", []Term{{1, "XA"}}, false},
+		{"lower case", []Term{{1, "xy"}}, false},
+	}
+	for _, tt := range tests {
+		if _, err := New(2, tt.terms...); (err == nil) != tt.ok {
+			t.Errorf("%s: got error %v", tt.name, err)
+		}
+	}
+}
+
+func TestMatrix(t *testing.T) {
+	tests := []struct {
+		name  string
+		terms []Term
+		want  matrix.Matrix
+	}{
+		{"Y", []Term{{1, "Y"}}, matrix.Matrix{{0, -1i}, {1i, 0}}},
+		// bit 0 is the most significant bit of the index
+		{"Z on bit 0", []Term{{1, "ZI"}}, matrix.Matrix{{1, 0, 0, 0}, {0, 1, 0, 0}, {0, 0, -1, 0}, {0, 0, 0, -1}}},
+		{"X on bit 1", []Term{{2, "IX"}}, matrix.Matrix{{0, 2, 0, 0}, {2, 0, 0, 0}, {0, 0, 0, 2}, {0, 0, 2, 0}}},
+		{"sum", []Term{{0.5, "ZZ"}, {-1, "II"}}, matrix.Matrix{{-0.5, 0, 0, 0}, {0, -1.5, 0, 0}, {0, 0, -1.5, 0}, {0, 0, 0, -0.5}}},
+	}
+	for _, tt := range tests {
+		h, err := New(len(tt.terms[0].Pauli), tt.terms...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := h.Matrix(); !got.Equals(tt.want, 1e-12) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSingleTerms(t *testing.T) {
+	// a single term has no Trotter error, so one step matches exp(-iHt) including its phase
+	const time = 0.3
+	c, s := complex(math.Cos(0.6), 0), complex(math.Sin(0.6), 0)
+	tests := []struct {
+		name string
+		term Term
+		// the exact exp(-i 2 P 0.3) where known in closed form
+		want matrix.Matrix
+	}{
+		// cos θ I - i sin θ Y, a wrong sign on the RX basis change gives its transpose
+		{"Y", Term{2, "Y"}, matrix.Matrix{{c, -s}, {s, c}}},
+		{"X", Term{2, "X"}, matrix.Matrix{{c, -1i * s}, {-1i * s, c}}},
+		{"Z", Term{2, "Z"}, matrix.Matrix{{cmplx.Exp(-0.6i), 0}, {0, cmplx.Exp(0.6i)}}},
+		// the identity is only a global phase e^(-iθ)
+		{"I", Term{2, "I"}, matrix.Matrix{{cmplx.Exp(-0.6i), 0}, {0, cmplx.Exp(-0.6i)}}},
+		{"II", Term{2, "II"}, nil},
+		{"YI", Term{2, "YI"}, nil},
+		{"IY", Term{-1.5, "IY"}, nil},
+		{"XY", Term{1, "XY"}, nil},
+		{"YZY", Term{0.8, "YZY"}, nil},
+		{"ZIZ", Term{-0.4, "ZIZ"}, nil},
+	}
+	for _, tt := range tests {
+		h, err := New(len(tt.term.Pauli), tt.term)
+		if err != nil {
+			t.Fatal(err)
+		}
+		exact := h.Exact(time)
+		if tt.want != nil && !exact.Equals(tt.want, 1e-9) {
+			t.Errorf("%s: exact %v, want %v", tt.name, exact, tt.want)
+		}
+		for _, order := range []int{1, 2, 4} {
+			circuit, err := h.Trotter(time, 1, order)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := circuit.Unitary(); !got.Equals(exact, 1e-9) {
+				t.Errorf("%s: order %d gave %v, want %v", tt.name, order, got, exact)
+			}
+		}
+	}
+}
+
+func TestIdentityTermIsAPhase(t *testing.T) {
+	h, err := New(2, Term{0.5, "II"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := h.Trotter(1, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ops := c.Ops()
+	if len(ops) != 1 || ops[0].Name != "Phase" || len(ops[0].Targets) != 1 || ops[0].Targets[0] != 0 {
+		t.Fatalf("got %v, want a single Phase on qubit 0", ops)
+	}
+	want := matrix.Matrix{{cmplx.Exp(-0.5i), 0}, {0, cmplx.Exp(-0.5i)}}
+	if !ops[0].Matrix.Equals(want, 1e-12) {
+		t.Errorf("got %v, want %v", ops[0].Matrix, want)
+	}
+}
+
+func TestTrotterConverges(t *testing.T) {
+	h := mixed(t)
+	const time = 1.0
+	exact := h.Exact(time)
+	errors := map[int][]float64{}
+	for _, order := range []int{1, 2, 4} {
+		for _, steps := range []int{1, 2, 4, 8, 16} {
+			c, err := h.Trotter(time, steps, order)
+			if err != nil {
+				t.Fatal(err)
+			}
+			errors[order] = append(errors[order], spectralNorm(c.Unitary().Subtract(exact)))
+		}
+		// doubling the steps divides the error by about 2^order
+		for i := 1; i < len(errors[order]); i++ {
+			ratio := errors[order][i-1] / errors[order][i]
+			if ratio < 0.75*math.Pow(2, float64(order)) {
+				t.Errorf("order %d: error %v fell by %.3g, want about %g", order, errors[order], ratio, math.Pow(2, float64(order)))
+				break
+			}
+		}
+	}
+	// a higher order is closer for the same number of steps
+	for i := range errors[1] {
+		if !(errors[4][i] < errors[2][i] && errors[2][i] < errors[1][i]) {
+			t.Errorf("step %d: errors by order %v, %v, %v", i, errors[1][i], errors[2][i], errors[4][i])
+		}
+	}
+	if errors[4][4] > 1e-6 {
+		t.Errorf("order 4 with 16 steps is still %v away", errors[4][4])
+	}
+}
+
+func TestTrotterErrors(t *testing.T) {
+	h := mixed(t)
+	if _, err := h.Trotter(1, 0, 2); err == nil {
+		t.Errorf("zero steps gave no error")
+	}
+	for _, order := range []int{0, 3, 6} {
+		if _, err := h.Trotter(1, 4, order); err == nil {
+			t.Errorf("order %d gave no error", order)
+		}
+	}
+	if _, err := h.Compare(qubit.Zero(3), 1, 4, 3); err == nil {
+		t.Errorf("Compare of order 3 gave no error")
+	}
+}
+
+func TestEvolve(t *testing.T) {
+	// Z turns |+> about the equator, e^(-it)|0> + e^(it)|1>
+	z, err := New(1, Term{1, "Z"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := complex(1/math.Sqrt2, 0)
+	plus := qubit.New(s, s)
+	got := z.Evolve(plus, 0.4)
+	want := qubit.New(s*cmplx.Exp(-0.4i), s*cmplx.Exp(0.4i))
+	if !got.Equals(want, 1e-9) {
+		t.Errorf("got %v, want %v", got.Amplitude(), want.Amplitude())
+	}
+	if !plus.Equals(qubit.New(s, s), 1e-12) {
+		t.Errorf("Evolve changed its input to %v", plus.Amplitude())
+	}
+
+	h := mixed(t)
+	input := qubit.New(1, 2i, 0, -1, 0.5, 0, 1i, 3)
+	for _, time := range []float64{0, 0.5, 2} {
+		if got, want := h.Evolve(input, time), input.Clone().Apply(h.Exact(time)); !got.Equals(want, 1e-12) {
+			t.Errorf("t = %g: got %v, want %v", time, got.Amplitude(), want.Amplitude())
+		}
+	}
+	// evolving forwards then backwards returns the input
+	if back := h.Evolve(h.Evolve(input, 0.7), -0.7); !back.Equals(input, 1e-9) {
+		t.Errorf("got %v back, want %v", back.Amplitude(), input.Amplitude())
+	}
+	report, err := h.Compare(input, 1, 16, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(report.Fidelity-1) > 1e-9 || report.Order != 4 || report.Steps != 16 || report.CNOTs == 0 {
+		t.Errorf("got %v", report)
+	}
+}
@@ -0,0 +1,161 @@
+package hamiltonian
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+
+	"github.com/benluxford/qe/circuit"
+	"github.com/benluxford/qe/gate"
+	"github.com/benluxford/qe/matrix"
+	"github.com/benluxford/qe/qubit"
+)
+
+// Report : How closely a Trotter circuit follows the exact evolution, OperatorError is the
+// spectral norm of the difference of the two unitaries and Fidelity compares the evolved states
+type Report struct {
+	Order, Steps        int
+	Gates, CNOTs, Depth int
+	OperatorError       float64
+	Fidelity            float64
+}
+
+// String : Returns the Report as e.g. "order 2, 10 steps: 120 gates (40 CX), depth 70, error 1.2e-04, fidelity 0.999999"
+func (r Report) String() string {
+	return fmt.Sprintf("order %d, %d steps: %d gates (%d CX), depth %d, error %.2g, fidelity %.6f",
+		r.Order, r.Steps, r.Gates, r.CNOTs, r.Depth, r.OperatorError, r.Fidelity)
+}
+
+// Trotter : Returns a Circuit approximating exp(-iHt) with the given number of Trotter-Suzuki
+// steps of order 1, 2 or 4. Each term exp(-i c P dt) is a basis change onto Z, a CX ladder
+// gathering the parity, RZ(2 c dt) and the ladder and basis change undone
+func (h *Hamiltonian) Trotter(t float64, steps, order int) (*circuit.Circuit, error) {
+	if steps < 1 {
+		return nil, fmt.Errorf("hamiltonian: at least one Trotter step is needed, got %d", steps)
+	}
+	dt := t / float64(steps)
+	// the sequence of (term, time) exponentials making up one step
+	var step []exponential
+	switch order {
+	case 1:
+		step = h.first(dt)
+	case 2:
+		step = h.second(dt)
+	case 4:
+		// Suzuki's recursion from second order, with p = 1 / (4 - 4^(1/3))
+		p := 1 / (4 - math.Cbrt(4))
+		for _, s := range []float64{p, p, 1 - 4*p, p, p} {
+			step = append(step, h.second(s*dt)...)
+		}
+	default:
+		return nil, fmt.Errorf("hamiltonian: Trotter order %d is not 1, 2 or 4", order)
+	}
+	c := circuit.New(h.bit)
+	for i := 0; i < steps; i++ {
+		for _, e := range step {
+			e.append(c)
+		}
+	}
+	return c, nil
+}
+
+// Compare : Returns the Report of a Trotter circuit against the exact evolution for time t,
+// with the fidelity of the two states evolved from the input
+func (h *Hamiltonian) Compare(input *qubit.Qubit, t float64, steps, order int) (Report, error) {
+	c, err := h.Trotter(t, steps, order)
+	if err != nil {
+		return Report{}, err
+	}
+	exact := h.Exact(t)
+	approximate := c.Unitary()
+	return Report{
+		Order:         order,
+		Steps:         steps,
+		Gates:         c.Len(),
+		CNOTs:         c.Counts()["CX"],
+		Depth:         c.Depth(),
+		OperatorError: spectralNorm(approximate.Subtract(exact)),
+		Fidelity:      c.Run(input).Fidelity(input.Clone().Apply(exact)),
+	}, nil
+}
+
+// exponential : exp(-i Coefficient Pauli time) for a single term
+type exponential struct {
+	term Term
+	time float64
+}
+
+// first : Returns one first order step, every term for the whole time in order
+func (h *Hamiltonian) first(dt float64) (step []exponential) {
+	for _, t := range h.terms {
+		step = append(step, exponential{t, dt})
+	}
+	return
+}
+
+// second : Returns one second order step, every term for half the time forwards then backwards,
+// with the two middle halves merged
+func (h *Hamiltonian) second(dt float64) (step []exponential) {
+	n := len(h.terms)
+	for i, t := range h.terms {
+		if i == n-1 {
+			step = append(step, exponential{t, dt})
+			continue
+		}
+		step = append(step, exponential{t, dt / 2})
+	}
+	for i := n - 2; i >= 0; i-- {
+		step = append(step, exponential{h.terms[i], dt / 2})
+	}
+	return
+}
+
+// append : Appends the exponential of a single Pauli term to the Circuit
+func (e exponential) append(c *circuit.Circuit) {
+	theta := e.term.Coefficient * e.time
+	active := []int{}
+	for i, p := range e.term.Pauli {
+		if p != 'I' {
+			active = append(active, i)
+		}
+	}
+	// the identity only contributes a global phase
+	if len(active) == 0 {
+		c.Gate("Phase", gate.I().Multiply(cmplx.Exp(complex(0, -theta))), 0)
+		return
+	}
+	// rotate each bit so its Pauli becomes Z
+	basis := func(undo bool) {
+		for _, q := range active {
+			switch e.term.Pauli[q] {
+			case 'X':
+				c.H(q)
+			case 'Y':
+				if undo {
+					c.RX(q, -math.Pi/2)
+				} else {
+					c.RX(q, math.Pi/2)
+				}
+			}
+		}
+	}
+	ladder := func(reverse bool) {
+		for i := range active[:len(active)-1] {
+			if reverse {
+				i = len(active) - 2 - i
+			}
+			c.CNOT(active[i], active[i+1])
+		}
+	}
+	basis(false)
+	ladder(false)
+	c.RZ(active[len(active)-1], 2*theta)
+	ladder(true)
+	basis(true)
+}
+
+// spectralNorm : Returns the largest singular value of a matrix
+func spectralNorm(m matrix.Matrix) float64 {
+	values, _ := m.Dagger().Apply(m).EigenHermitian()
+	return math.Sqrt(math.Max(values[0], 0))
+}
@@ -0,0 +1,260 @@
+package matrix
+
+import (
+	"fmt"
+	"math/cmplx"
+)
+
+// Dense : A matrix of complex numbers held in a single contiguous, row major slice
+// with the number of rows and columns stored alongside the data
+type Dense struct {
+	rows, columns int
+	data          []complex128
+}
+
+// NewDense : Returns a pointer to a new zeroed Dense matrix of the given size
+func NewDense(rows, columns int) *Dense {
+	return &Dense{rows, columns, make([]complex128, rows*columns)}
+}
+
+// NewDenseFrom : Returns a pointer to a new Dense matrix copied from a Matrix
+func NewDenseFrom(input Matrix) (d *Dense) {
+	// get the number of rows and columns
+	rows, columns := input.Dimension()
+	// create the new dense matrix
+	d = NewDense(rows, columns)
+	// copy each row into the backing slice
+	for i := 0; i < rows; i++ {
+		copy(d.data[i*columns:(i+1)*columns], input[i])
+	}
+	return
+}
+
+// Identity : Returns a pointer to a new Dense identity matrix of size n
+func Identity(n int) (d *Dense) {
+	d = NewDense(n, n)
+	for i := 0; i < n; i++ {
+		d.data[i*n+i] = 1
+	}
+	return
+}
+
+// Matrix : Returns the Dense matrix converted back to a Matrix
+func (d *Dense) Matrix() (m Matrix) {
+	m = make(Matrix, d.rows)
+	// each row gets its own slice so the Matrix does not alias the Dense data
+	for i := 0; i < d.rows; i++ {
+		m[i] = make([]complex128, d.columns)
+		copy(m[i], d.data[i*d.columns:(i+1)*d.columns])
+	}
+	return
+}
+
+// Dimension : Returns the stored number of rows and columns
+func (d *Dense) Dimension() (rows, columns int) {
+	return d.rows, d.columns
+}
+
+// Data : Returns the row major backing slice, changes are reflected in the matrix
+func (d *Dense) Data() []complex128 {
+	return d.data
+}
+
+// At : Returns the component at row i, column j
+func (d *Dense) At(i, j int) complex128 {
+	return d.data[i*d.columns+j]
+}
+
+// Set : Sets the component at row i, column j
+func (d *Dense) Set(i, j int, value complex128) {
+	d.data[i*d.columns+j] = value
+}
+
+// Clone : Returns a pointer to a copy of the current Dense matrix
+func (d *Dense) Clone() *Dense {
+	data := make([]complex128, len(d.data))
+	copy(data, d.data)
+	return &Dense{d.rows, d.columns, data}
+}
+
+// Equals : Returns bool, if input matrix matches the current matrix = true
+func (d *Dense) Equals(input *Dense, eps ...float64) bool {
+	// if the columns or rows do not match return false
+	if d.rows != input.rows || d.columns != input.columns {
+		return false
+	}
+	e := Eps(eps...)
+	for i := range d.data {
+		if cmplx.Abs(d.data[i]-input.data[i]) > e {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply : Returns a new matrix of the input multiplied by the current matrix,
+// matching Matrix.Apply (input * d)
+func (d *Dense) Apply(input *Dense) *Dense {
+	return d.ApplyTo(nil, input)
+}
+
+// ApplyTo : Writes input * d into dst and returns it, dst is allocated when nil or
+// resized when its backing slice is too small. dst must not be d or input
+func (d *Dense) ApplyTo(dst, input *Dense) *Dense {
+	dst = reuse(dst, input.rows, d.columns)
+	// for all rows in the input
+	for i := 0; i < input.rows; i++ {
+		row := dst.data[i*dst.columns : (i+1)*dst.columns]
+		// clear the destination row before accumulating
+		for j := range row {
+			row[j] = 0
+		}
+		// accumulate input[i][k] * d[k] into the row, walking both slices in order
+		for k := 0; k < input.columns; k++ {
+			component := input.data[i*input.columns+k]
+			if component == 0 {
+				continue
+			}
+			dRow := d.data[k*d.columns : (k+1)*d.columns]
+			for j := range row {
+				row[j] += component * dRow[j]
+			}
+		}
+	}
+	return dst
+}
+
+// ApplyInPlace : Replaces the current matrix with input * d and returns it,
+// only a single row of scratch space is allocated
+func (d *Dense) ApplyInPlace(input *Dense) *Dense {
+	// the result has the dimensions of d only when input is square
+	if input.rows != input.columns {
+		panic("matrix: ApplyInPlace requires a square input")
+	}
+	if input.columns != d.rows {
+		panic(fmt.Sprintf("matrix: ApplyInPlace of a %dx%d input to a %dx%d matrix", input.rows, input.columns, d.rows, d.columns))
+	}
+	n := d.rows
+	column := make([]complex128, n)
+	// every column of the result only depends on the same column of d
+	for j := 0; j < d.columns; j++ {
+		// take a copy of the current column
+		for k := 0; k < n; k++ {
+			column[k] = d.data[k*d.columns+j]
+		}
+		// write back input * column
+		for i := 0; i < n; i++ {
+			var component complex128
+			for k := 0; k < n; k++ {
+				component += input.data[i*n+k] * column[k]
+			}
+			d.data[i*d.columns+j] = component
+		}
+	}
+	return d
+}
+
+// Add : Returns a new matrix with the sum of the current and input components
+func (d *Dense) Add(input *Dense) *Dense {
+	return d.AddTo(nil, input)
+}
+
+// AddTo : Writes the sum of the current and input matrix into dst and returns it
+func (d *Dense) AddTo(dst, input *Dense) *Dense {
+	dst = reuse(dst, d.rows, d.columns)
+	for i := range d.data {
+		dst.data[i] = d.data[i] + input.data[i]
+	}
+	return dst
+}
+
+// AddInPlace : Adds the input components to the current matrix and returns it
+func (d *Dense) AddInPlace(input *Dense) *Dense {
+	return d.AddTo(d, input)
+}
+
+// Multiply : Returns a new matrix with each component multiplied by the input
+func (d *Dense) Multiply(input complex128) *Dense {
+	return d.MultiplyTo(nil, input)
+}
+
+// MultiplyTo : Writes the current matrix multiplied by the input into dst and returns it
+func (d *Dense) MultiplyTo(dst *Dense, input complex128) *Dense {
+	dst = reuse(dst, d.rows, d.columns)
+	for i := range d.data {
+		dst.data[i] = input * d.data[i]
+	}
+	return dst
+}
+
+// MultiplyInPlace : Multiplies each component of the current matrix by the input and returns it
+func (d *Dense) MultiplyInPlace(input complex128) *Dense {
+	return d.MultiplyTo(d, input)
+}
+
+// TensorProduct : Returns a new matrix holding the tensor product of the current and input matrix
+func (d *Dense) TensorProduct(input *Dense) *Dense {
+	return d.TensorProductTo(nil, input)
+}
+
+// TensorProductTo : Writes the tensor product of the current and input matrix into dst
+// and returns it. dst must not be d or input
+func (d *Dense) TensorProductTo(dst, input *Dense) *Dense {
+	columns := d.columns * input.columns
+	dst = reuse(dst, d.rows*input.rows, columns)
+	// for each component of the current matrix
+	for i := 0; i < d.rows; i++ {
+		for j := 0; j < d.columns; j++ {
+			component := d.data[i*d.columns+j]
+			// write the scaled input block at (i, j)
+			for k := 0; k < input.rows; k++ {
+				offset := (i*input.rows+k)*columns + j*input.columns
+				inputRow := input.data[k*input.columns : (k+1)*input.columns]
+				for l, value := range inputRow {
+					dst.data[offset+l] = component * value
+				}
+			}
+		}
+	}
+	return dst
+}
+
+// TensorProductInPlace : Replaces the current matrix with its tensor product with the input
+// and returns it. The backing slice is grown if needed, existing capacity is reused
+func (d *Dense) TensorProductInPlace(input *Dense) *Dense {
+	rows, columns := d.rows*input.rows, d.columns*input.columns
+	oldColumns := d.columns
+	if cap(d.data) < rows*columns {
+		grown := make([]complex128, rows*columns)
+		copy(grown, d.data)
+		d.data = grown
+	}
+	d.data = d.data[:rows*columns]
+	// every component moves to an index at or beyond its own, so walking backwards
+	// reads each component before anything can overwrite it
+	for s := d.rows*oldColumns - 1; s >= 0; s-- {
+		i, j := s/oldColumns, s%oldColumns
+		component := d.data[s]
+		for k := input.rows - 1; k >= 0; k-- {
+			offset := (i*input.rows+k)*columns + j*input.columns
+			for l := input.columns - 1; l >= 0; l-- {
+				d.data[offset+l] = component * input.data[k*input.columns+l]
+			}
+		}
+	}
+	d.rows, d.columns = rows, columns
+	return d
+}
+
+// reuse : Returns dst sized to rows * columns, allocating when dst is nil or too small
+func reuse(dst *Dense, rows, columns int) *Dense {
+	if dst == nil {
+		return NewDense(rows, columns)
+	}
+	if cap(dst.data) < rows*columns {
+		dst.data = make([]complex128, rows*columns)
+	}
+	dst.data = dst.data[:rows*columns]
+	dst.rows, dst.columns = rows, columns
+	return dst
+}
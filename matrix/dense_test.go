@@ -0,0 +1,171 @@
+package matrix
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// randomMatrix : Returns a rows by columns Matrix of random components
+func randomMatrix(rows, columns int, rng *rand.Rand) Matrix {
+	m := make(Matrix, rows)
+	for i := range m {
+		m[i] = make([]complex128, columns)
+		for j := range m[i] {
+			m[i][j] = complex(rng.NormFloat64(), rng.NormFloat64())
+		}
+	}
+	return m
+}
+
+// product : Returns input * m for matrices of any compatible size
+func product(input, m Matrix) Matrix {
+	out := make(Matrix, len(input))
+	for i := range out {
+		out[i] = make([]complex128, len(m[0]))
+		for j := range out[i] {
+			for k := range m {
+				out[i][j] += input[i][k] * m[k][j]
+			}
+		}
+	}
+	return out
+}
+
+func TestDenseConversion(t *testing.T) {
+	m := randomMatrix(2, 3, rand.New(rand.NewSource(1)))
+	d := NewDenseFrom(m)
+	if rows, columns := d.Dimension(); rows != 2 || columns != 3 {
+		t.Errorf("got dimension %dx%d, want 2x3", rows, columns)
+	}
+	if d.At(1, 2) != m[1][2] {
+		t.Errorf("At(1, 2) = %v, want %v", d.At(1, 2), m[1][2])
+	}
+	back := d.Matrix()
+	back[0][0] = 7
+	if !d.Matrix().Equals(m) || d.At(0, 0) == 7 {
+		t.Error("Matrix does not copy the Dense data")
+	}
+	if !NewDenseFrom(Identity(3).Matrix()).Equals(Identity(3)) {
+		t.Error("identity does not round trip")
+	}
+}
+
+// denseCase : The result of a Dense method and the Matrix it should hold
+type denseCase struct {
+	name string
+	got  *Dense
+	want Matrix
+}
+
+func TestDenseMatchesMatrix(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	sizes := [][2]int{{1, 1}, {2, 3}, {3, 2}, {4, 4}, {1, 5}, {5, 1}}
+	for _, size := range sizes {
+		rows, columns := size[0], size[1]
+		m, other := randomMatrix(rows, columns, rng), randomMatrix(rows, columns, rng)
+		input := randomMatrix(columns+1, rows, rng)
+		kron := randomMatrix(columns, rows+1, rng)
+		d := NewDenseFrom(m)
+		scale := complex(0.5, -2)
+		tests := []denseCase{
+			{"Apply", d.Apply(NewDenseFrom(input)), product(input, m)},
+			{"ApplyTo", d.ApplyTo(NewDense(1, 1), NewDenseFrom(input)), product(input, m)},
+			{"Add", d.Add(NewDenseFrom(other)), m.Add(other)},
+			{"AddTo", d.AddTo(NewDense(9, 9), NewDenseFrom(other)), m.Add(other)},
+			{"AddInPlace", d.Clone().AddInPlace(NewDenseFrom(other)), m.Add(other)},
+			{"Multiply", d.Multiply(scale), m.Multiply(scale)},
+			{"MultiplyTo", d.MultiplyTo(nil, scale), m.Multiply(scale)},
+			{"MultiplyInPlace", d.Clone().MultiplyInPlace(scale), m.Multiply(scale)},
+			{"TensorProduct", d.TensorProduct(NewDenseFrom(kron)), m.TensorProduct(kron)},
+			{"TensorProductTo", d.TensorProductTo(NewDense(2, 2), NewDenseFrom(kron)), m.TensorProduct(kron)},
+			{"TensorProductInPlace", d.Clone().TensorProductInPlace(NewDenseFrom(kron)), m.TensorProduct(kron)},
+		}
+		// ApplyInPlace needs a square input of the same height as d, as Matrix.Apply does
+		square := randomMatrix(rows, rows, rng)
+		tests = append(tests, denseCase{"ApplyInPlace", d.Clone().ApplyInPlace(NewDenseFrom(square)), product(square, m)})
+		if rows == columns {
+			tests = append(tests, denseCase{"Apply square", d.Apply(NewDenseFrom(square)), m.Apply(square)})
+		}
+		for _, tt := range tests {
+			if !tt.got.Matrix().Equals(tt.want, 1e-12) {
+				t.Errorf("%dx%d %s: got %v, want %v", rows, columns, tt.name, tt.got.Matrix(), tt.want)
+			}
+		}
+		if !d.Matrix().Equals(m) {
+			t.Errorf("%dx%d: the receiver changed", rows, columns)
+		}
+	}
+}
+
+func TestDenseTensorProductInPlaceReusesCapacity(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	for _, size := range [][4]int{{2, 3, 3, 2}, {1, 4, 2, 1}, {3, 1, 1, 3}, {2, 2, 2, 2}} {
+		m, input := randomMatrix(size[0], size[1], rng), randomMatrix(size[2], size[3], rng)
+		d := NewDenseFrom(m)
+		// with spare capacity the product is built over the original components, which the
+		// backward walk must read before it overwrites them
+		spare := make([]complex128, len(d.data), len(d.data)*size[2]*size[3])
+		copy(spare, d.data)
+		d.data = spare
+		d.TensorProductInPlace(NewDenseFrom(input))
+		if &d.data[0] != &spare[0] {
+			t.Errorf("%v: capacity was not reused", size)
+		}
+		if !d.Matrix().Equals(m.TensorProduct(input), 1e-12) {
+			t.Errorf("%v: got %v, want %v", size, d.Matrix(), m.TensorProduct(input))
+		}
+	}
+}
+
+func TestDenseApplyInPlacePanicsOnMismatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		d, input *Dense
+	}{
+		{"not square", NewDense(2, 2), NewDense(2, 3)},
+		{"too large", NewDense(2, 2), Identity(4)},
+		{"too small", NewDense(3, 2), Identity(2)},
+	}
+	for _, tt := range tests {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected a panic", tt.name)
+				}
+			}()
+			tt.d.ApplyInPlace(tt.input)
+		}()
+	}
+}
+
+func BenchmarkApply(b *testing.B) {
+	rng := rand.New(rand.NewSource(4))
+	m, input := randomMatrix(64, 64, rng), randomMatrix(64, 64, rng)
+	b.Run("Matrix", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			m.Apply(input)
+		}
+	})
+	d, dInput, dst := NewDenseFrom(m), NewDenseFrom(input), NewDense(64, 64)
+	b.Run("Dense", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			d.ApplyTo(dst, dInput)
+		}
+	})
+}
+
+func BenchmarkTensorProduct(b *testing.B) {
+	rng := rand.New(rand.NewSource(5))
+	m, input := randomMatrix(16, 16, rng), randomMatrix(8, 8, rng)
+	b.Run("Matrix", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			m.TensorProduct(input)
+		}
+	})
+	d, dInput, dst := NewDenseFrom(m), NewDenseFrom(input), NewDense(128, 128)
+	b.Run("Dense", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			d.TensorProductTo(dst, dInput)
+		}
+	})
+}
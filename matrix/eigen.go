@@ -0,0 +1,257 @@
+package matrix
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"sort"
+)
+
+// eigenEps : The tolerance used to recognise hermitian and unitary matrices
+const eigenEps = 1e-9
+
+// EigenHermitian : Returns the eigenvalues of a hermitian matrix in decreasing order and the
+// matching orthonormal eigenvectors as columns, using cyclic complex Jacobi rotations
+func (m Matrix) EigenHermitian() (values []float64, vectors Matrix) {
+	n := len(m)
+	a := make(Matrix, n)
+	vectors = make(Matrix, n)
+	scale := 0.0
+	for i := range a {
+		a[i] = append([]complex128{}, m[i]...)
+		vectors[i] = make([]complex128, n)
+		vectors[i][i] = 1
+		for _, x := range a[i] {
+			scale += real(x)*real(x) + imag(x)*imag(x)
+		}
+	}
+	for sweep := 0; sweep < 100; sweep++ {
+		off := 0.0
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				off += 2 * math.Pow(cmplx.Abs(a[p][q]), 2)
+			}
+		}
+		if off <= 1e-30*scale || off == 0 {
+			break
+		}
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				if cmplx.Abs(a[p][q]) == 0 {
+					continue
+				}
+				rotate(a, vectors, p, q)
+			}
+		}
+	}
+	// sort the eigenpairs by decreasing eigenvalue
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return real(a[order[i]][order[i]]) > real(a[order[j]][order[j]]) })
+	values = make([]float64, n)
+	sorted := make(Matrix, n)
+	for i := range sorted {
+		sorted[i] = make([]complex128, n)
+	}
+	for k, o := range order {
+		values[k] = real(a[o][o])
+		for i := range sorted {
+			sorted[i][k] = vectors[i][o]
+		}
+	}
+	return values, sorted
+}
+
+// rotate : Zeros a[p][q] of a hermitian matrix with the unitary G on rows and columns p and q,
+// a = G^dagger a G and vectors = vectors G
+func rotate(a, vectors Matrix, p, q int) {
+	apq := a[p][q]
+	phase := cmplx.Exp(complex(0, -cmplx.Phase(apq)))
+	magnitude := cmplx.Abs(apq)
+	// the real Jacobi rotation once the phase of a[p][q] is removed
+	tau := (real(a[q][q]) - real(a[p][p])) / (2 * magnitude)
+	t := 1 / (math.Abs(tau) + math.Sqrt(1+tau*tau))
+	if tau < 0 {
+		t = -t
+	}
+	c := 1 / math.Sqrt(1+t*t)
+	s := t * c
+	gpp, gpq := complex(c, 0), complex(s, 0)
+	gqp, gqq := complex(-s, 0)*phase, complex(c, 0)*phase
+	for i := range a {
+		x, y := a[i][p], a[i][q]
+		a[i][p], a[i][q] = x*gpp+y*gqp, x*gpq+y*gqq
+	}
+	for j := range a {
+		x, y := a[p][j], a[q][j]
+		a[p][j], a[q][j] = cmplx.Conj(gpp)*x+cmplx.Conj(gqp)*y, cmplx.Conj(gpq)*x+cmplx.Conj(gqq)*y
+	}
+	for i := range vectors {
+		x, y := vectors[i][p], vectors[i][q]
+		vectors[i][p], vectors[i][q] = x*gpp+y*gqp, x*gpq+y*gqq
+	}
+	a[p][q], a[q][p] = 0, 0
+}
+
+// EigenUnitary : Returns the eigenvalues of a unitary matrix, all of magnitude one, and the
+// matching orthonormal eigenvectors as columns. A unitary matrix is normal, so its Schur form
+// Q^dagger U Q is diagonal and the Schur vectors Q are its eigenvectors. Returns an error if the
+// iteration does not reach a diagonal of unit magnitude
+func (m Matrix) EigenUnitary() (values []complex128, vectors Matrix, err error) {
+	if !m.IsUnitary(eigenEps) {
+		return nil, nil, fmt.Errorf("matrix: matrix is not unitary")
+	}
+	t, vectors, err := m.schur()
+	if err != nil {
+		return nil, nil, err
+	}
+	values = make([]complex128, len(m))
+	for i := range t {
+		for j := range t[i] {
+			if i != j && cmplx.Abs(t[i][j]) > 1e-7 {
+				return nil, nil, fmt.Errorf("matrix: Schur form of the unitary is not diagonal")
+			}
+		}
+		if math.Abs(cmplx.Abs(t[i][i])-1) > 1e-7 {
+			return nil, nil, fmt.Errorf("matrix: eigenvalue %v of the unitary is not of magnitude one", t[i][i])
+		}
+		values[i] = t[i][i]
+	}
+	return
+}
+
+// schur : Returns the complex Schur form T, upper triangular, and the unitary Q with
+// m = Q T Q^dagger. Householder reflections bring m to Hessenberg form, then shifted QR steps
+// made of Givens rotations drive its subdiagonal to zero from the bottom up
+func (m Matrix) schur() (t, q Matrix, err error) {
+	n := len(m)
+	t, q = make(Matrix, n), identity(n)
+	for i := range t {
+		t[i] = append([]complex128{}, m[i]...)
+	}
+	// Hessenberg form, reflecting column k below the subdiagonal onto its first entry
+	for k := 0; k+2 < n; k++ {
+		v := make([]complex128, n)
+		norm := 0.0
+		for i := k + 1; i < n; i++ {
+			v[i] = t[i][k]
+			norm += real(v[i])*real(v[i]) + imag(v[i])*imag(v[i])
+		}
+		norm = math.Sqrt(norm)
+		if norm == 0 {
+			continue
+		}
+		// the sign that avoids cancellation in v = x - alpha e1
+		alpha := complex(-norm, 0)
+		if v[k+1] != 0 {
+			alpha *= v[k+1] / complex(cmplx.Abs(v[k+1]), 0)
+		}
+		v[k+1] -= alpha
+		length := 0.0
+		for i := k + 1; i < n; i++ {
+			length += real(v[i])*real(v[i]) + imag(v[i])*imag(v[i])
+		}
+		if length == 0 {
+			continue
+		}
+		scale := complex(math.Sqrt(length), 0)
+		for i := k + 1; i < n; i++ {
+			v[i] /= scale
+		}
+		// P = I - 2 v v^dagger on both sides of t, and on the right of q
+		for j := 0; j < n; j++ {
+			var dot complex128
+			for i := k + 1; i < n; i++ {
+				dot += cmplx.Conj(v[i]) * t[i][j]
+			}
+			for i := k + 1; i < n; i++ {
+				t[i][j] -= 2 * v[i] * dot
+			}
+		}
+		for _, a := range []Matrix{t, q} {
+			for i := 0; i < n; i++ {
+				var dot complex128
+				for j := k + 1; j < n; j++ {
+					dot += a[i][j] * v[j]
+				}
+				for j := k + 1; j < n; j++ {
+					a[i][j] -= 2 * dot * cmplx.Conj(v[j])
+				}
+			}
+		}
+		for i := k + 2; i < n; i++ {
+			t[i][k] = 0
+		}
+	}
+
+	type givens struct{ c, s complex128 }
+	iterations := 0
+	for hi := n - 1; hi > 0; {
+		// the active block runs from lo to hi, split off where the subdiagonal vanishes
+		lo := hi
+		for ; lo > 0; lo-- {
+			if cmplx.Abs(t[lo][lo-1]) <= 1e-15*(cmplx.Abs(t[lo][lo])+cmplx.Abs(t[lo-1][lo-1])) {
+				t[lo][lo-1] = 0
+				break
+			}
+		}
+		if lo == hi {
+			hi--
+			iterations = 0
+			continue
+		}
+		if iterations++; iterations > 100*n {
+			return nil, nil, fmt.Errorf("matrix: Schur iteration did not converge")
+		}
+		// Wilkinson's shift, the eigenvalue of the trailing 2x2 nearer its last entry, with an
+		// occasional exceptional shift to break cycles
+		a, b, c, d := t[hi-1][hi-1], t[hi-1][hi], t[hi][hi-1], t[hi][hi]
+		half := (a + d) / 2
+		root := cmplx.Sqrt(half*half - (a*d - b*c))
+		shift := half + root
+		if cmplx.Abs(half-root-d) < cmplx.Abs(shift-d) {
+			shift = half - root
+		}
+		if iterations%11 == 0 {
+			shift = d + complex(cmplx.Abs(c), 0)
+		}
+		for k := lo; k <= hi; k++ {
+			t[k][k] -= shift
+		}
+		// QR of the shifted block by rotations on the left, then RQ by their inverses on the right
+		rotations := make([]givens, 0, hi-lo)
+		for k := lo; k < hi; k++ {
+			x, y := t[k][k], t[k+1][k]
+			r := math.Hypot(cmplx.Abs(x), cmplx.Abs(y))
+			g := givens{1, 0}
+			if r != 0 {
+				g = givens{x / complex(r, 0), y / complex(r, 0)}
+			}
+			for j := k; j < n; j++ {
+				p, s := t[k][j], t[k+1][j]
+				t[k][j] = cmplx.Conj(g.c)*p + cmplx.Conj(g.s)*s
+				t[k+1][j] = -g.s*p + g.c*s
+			}
+			rotations = append(rotations, g)
+		}
+		for i, g := range rotations {
+			k := lo + i
+			for row := 0; row <= k+1; row++ {
+				p, s := t[row][k], t[row][k+1]
+				t[row][k] = p*g.c + s*g.s
+				t[row][k+1] = -p*cmplx.Conj(g.s) + s*cmplx.Conj(g.c)
+			}
+			for row := 0; row < n; row++ {
+				p, s := q[row][k], q[row][k+1]
+				q[row][k] = p*g.c + s*g.s
+				q[row][k+1] = -p*cmplx.Conj(g.s) + s*cmplx.Conj(g.c)
+			}
+		}
+		for k := lo; k <= hi; k++ {
+			t[k][k] += shift
+		}
+	}
+	return t, q, nil
+}
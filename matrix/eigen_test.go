@@ -0,0 +1,179 @@
+package matrix
+
+import (
+	"math"
+	"math/cmplx"
+	"math/rand"
+	"testing"
+)
+
+// randomUnitary : Returns a random n by n unitary, the Gram-Schmidt columns of a random matrix
+func randomUnitary(n int, rng *rand.Rand) Matrix {
+	columns := make([][]complex128, n)
+	for k := range columns {
+		v := make([]complex128, n)
+		for i := range v {
+			v[i] = complex(rng.NormFloat64(), rng.NormFloat64())
+		}
+		for _, u := range columns[:k] {
+			var dot complex128
+			for i := range u {
+				dot += cmplx.Conj(u[i]) * v[i]
+			}
+			for i := range v {
+				v[i] -= dot * u[i]
+			}
+		}
+		norm := 0.0
+		for _, x := range v {
+			norm += real(x)*real(x) + imag(x)*imag(x)
+		}
+		for i := range v {
+			v[i] /= complex(math.Sqrt(norm), 0)
+		}
+		columns[k] = v
+	}
+	m := make(Matrix, n)
+	for i := range m {
+		m[i] = make([]complex128, n)
+		for j := range m[i] {
+			m[i][j] = columns[j][i]
+		}
+	}
+	return m
+}
+
+// diagonalOf : Returns the diagonal matrix of the values
+func diagonalOf(values ...complex128) Matrix {
+	d := identity(len(values))
+	for i, v := range values {
+		d[i][i] = v
+	}
+	return d
+}
+
+// checkEigenUnitary : Fails unless the vectors are orthonormal, each eigenvalue has magnitude
+// one and U = V D V^dagger
+func checkEigenUnitary(t *testing.T, name string, u Matrix) {
+	t.Helper()
+	values, vectors, err := u.EigenUnitary()
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	if !vectors.IsUnitary(1e-9) {
+		t.Errorf("%s: eigenvectors are not orthonormal", name)
+	}
+	for _, v := range values {
+		if math.Abs(cmplx.Abs(v)-1) > 1e-9 {
+			t.Errorf("%s: eigenvalue %v is not of magnitude one", name, v)
+		}
+	}
+	if rebuilt := multiply(multiply(vectors, diagonalOf(values...)), vectors.Dagger()); !rebuilt.Equals(u, 1e-9) {
+		t.Errorf("%s: V D V^dagger is not U", name)
+	}
+}
+
+func TestEigenUnitary(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for n := 1; n <= 16; n *= 2 {
+		for trial := 0; trial < 20; trial++ {
+			checkEigenUnitary(t, "random", randomUnitary(n, rng))
+		}
+	}
+	h := Matrix{{1 / math.Sqrt2, 1 / math.Sqrt2}, {1 / math.Sqrt2, -1 / math.Sqrt2}}
+	checkEigenUnitary(t, "H", h)
+	checkEigenUnitary(t, "identity", identity(4))
+	checkEigenUnitary(t, "-identity", identity(4).Multiply(-1))
+	// eigenvalues placed symmetrically about any one angle, which defeat a fixed mix of the
+	// hermitian and antihermitian parts
+	for _, phi := range []float64{0, math.Atan(0.5772156649015329), 1, math.Pi / 2, 3} {
+		d := diagonalOf(cmplx.Exp(complex(0, phi+1)), cmplx.Exp(complex(0, phi-1)))
+		checkEigenUnitary(t, "symmetric pair", multiply(multiply(h, d), h))
+	}
+	// repeated eigenvalues with a random basis
+	v := randomUnitary(4, rng)
+	d := diagonalOf(1i, 1i, -1, cmplx.Exp(0.3i))
+	checkEigenUnitary(t, "degenerate", multiply(multiply(v, d), v.Dagger()))
+}
+
+func TestEigenUnitaryRejectsNonUnitary(t *testing.T) {
+	if _, _, err := (Matrix{{1, 1}, {0, 1}}).EigenUnitary(); err == nil {
+		t.Error("expected an error for a non unitary matrix")
+	}
+}
+
+func TestEigenHermitian(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	u := randomUnitary(6, rng)
+	want := []float64{3, 2, 2, 0.5, -1, -4}
+	d := identity(6)
+	for i, x := range want {
+		d[i][i] = complex(x, 0)
+	}
+	m := multiply(multiply(u, d), u.Dagger())
+	values, vectors := m.EigenHermitian()
+	for i := range want {
+		if math.Abs(values[i]-want[i]) > 1e-9 {
+			t.Errorf("got eigenvalues %v, want %v", values, want)
+			break
+		}
+	}
+	rebuilt := make(Matrix, 6)
+	for i := range rebuilt {
+		rebuilt[i] = make([]complex128, 6)
+	}
+	for k, x := range values {
+		for i := range rebuilt {
+			for j := range rebuilt {
+				rebuilt[i][j] += vectors[i][k] * complex(x, 0) * cmplx.Conj(vectors[j][k])
+			}
+		}
+	}
+	if !rebuilt.Equals(m, 1e-9) {
+		t.Error("V D V^dagger is not M")
+	}
+}
+
+func TestLogAndSqrt(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	h := Matrix{{1 / math.Sqrt2, 1 / math.Sqrt2}, {1 / math.Sqrt2, -1 / math.Sqrt2}}
+	inputs := []Matrix{randomUnitary(2, rng), randomUnitary(4, rng), randomUnitary(8, rng)}
+	for _, phi := range []float64{math.Atan(0.5772156649015329), 0.7} {
+		d := diagonalOf(cmplx.Exp(complex(0, phi+1)), cmplx.Exp(complex(0, phi-1)))
+		inputs = append(inputs, multiply(multiply(h, d), h))
+	}
+	for i, u := range inputs {
+		log, err := u.Log()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !log.Exp().Equals(u, 1e-9) {
+			t.Errorf("input %d: Exp(Log(U)) is not U", i)
+		}
+		sqrt, err := u.Sqrt()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !multiply(sqrt, sqrt).Equals(u, 1e-9) {
+			t.Errorf("input %d: Sqrt(U)^2 is not U", i)
+		}
+	}
+}
+
+func TestDeterminant(t *testing.T) {
+	tests := []struct {
+		m    Matrix
+		want complex128
+	}{
+		{Matrix{{2}}, 2},
+		{Matrix{{1, 2}, {3, 4}}, -2},
+		{Matrix{{0, 1}, {1, 0}}, -1},
+		{Matrix{{1i, 0, 0}, {0, 2, 0}, {5, 7, 3}}, 6i},
+		{Matrix{{1, 2}, {2, 4}}, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.m.Determinant(); cmplx.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("det %v = %v, want %v", tt.m, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package matrix
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+)
+
+// Exp : Returns the matrix exponential e^m of any square matrix, by scaling m until its norm is
+// below one half, summing the Taylor series and squaring back up
+func (m Matrix) Exp() Matrix {
+	n := len(m)
+	// the infinity norm bounds how far the series has to be scaled
+	norm := 0.0
+	for i := range m {
+		row := 0.0
+		for _, x := range m[i] {
+			row += cmplx.Abs(x)
+		}
+		norm = math.Max(norm, row)
+	}
+	squarings := 0
+	if norm > 0.5 {
+		squarings = int(math.Ceil(math.Log2(norm / 0.5)))
+	}
+	scaled := m.Multiply(complex(math.Pow(2, -float64(squarings)), 0))
+
+	// sum the series until the terms no longer change the result
+	exp, term := identity(n), identity(n)
+	for k := 1; k < 40; k++ {
+		term = multiply(term, scaled).Multiply(complex(1/float64(k), 0))
+		exp = exp.Add(term)
+		if infinity(term) < 1e-17 {
+			break
+		}
+	}
+	for i := 0; i < squarings; i++ {
+		exp = multiply(exp, exp)
+	}
+	return exp
+}
+
+// Function : Returns f(m) for a hermitian or unitary matrix, V f(D) V^dagger with f applied to
+// each eigenvalue of its eigendecomposition V D V^dagger
+func (m Matrix) Function(f func(complex128) complex128) (Matrix, error) {
+	var values []complex128
+	var vectors Matrix
+	switch {
+	case m.IsHermite(eigenEps):
+		hermitian, v := m.EigenHermitian()
+		for _, x := range hermitian {
+			values = append(values, complex(x, 0))
+		}
+		vectors = v
+	case m.IsUnitary(eigenEps):
+		var err error
+		if values, vectors, err = m.EigenUnitary(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("matrix: matrix functions need a hermitian or unitary matrix")
+	}
+	n := len(m)
+	result := make(Matrix, n)
+	for i := range result {
+		result[i] = make([]complex128, n)
+	}
+	for k, value := range values {
+		fx := f(value)
+		for i := 0; i < n; i++ {
+			if vectors[i][k] == 0 {
+				continue
+			}
+			for j := 0; j < n; j++ {
+				result[i][j] += vectors[i][k] * fx * cmplx.Conj(vectors[j][k])
+			}
+		}
+	}
+	return result, nil
+}
+
+// Log : Returns the principal logarithm of a hermitian or unitary matrix, for a unitary U it is
+// iH with H hermitian and U = e^(iH)
+func (m Matrix) Log() (Matrix, error) {
+	return m.Function(cmplx.Log)
+}
+
+// Sqrt : Returns the principal square root of a hermitian or unitary matrix
+func (m Matrix) Sqrt() (Matrix, error) {
+	return m.Function(cmplx.Sqrt)
+}
+
+// identity : Returns the n by n identity
+func identity(n int) Matrix {
+	m := make(Matrix, n)
+	for i := range m {
+		m[i] = make([]complex128, n)
+		m[i][i] = 1
+	}
+	return m
+}
+
+// multiply : Returns the product a b, in the order written
+func multiply(a, b Matrix) Matrix {
+	product := make(Matrix, len(a))
+	for i := range a {
+		product[i] = make([]complex128, len(b[0]))
+		for k, x := range a[i] {
+			if x == 0 {
+				continue
+			}
+			for j, y := range b[k] {
+				product[i][j] += x * y
+			}
+		}
+	}
+	return product
+}
+
+// infinity : Returns the largest absolute entry of the matrix
+func infinity(m Matrix) (largest float64) {
+	for i := range m {
+		for _, x := range m[i] {
+			largest = math.Max(largest, cmplx.Abs(x))
+		}
+	}
+	return
+}
@@ -1,6 +1,9 @@
 package matrix
 
-import "math/cmplx"
+import (
+	"fmt"
+	"math/cmplx"
+)
 
 // Matrix : A matrix of complex numbers
 type Matrix [][]complex128
@@ -65,6 +68,51 @@ func (m Matrix) Equals(input Matrix, eps ...float64) (match bool) {
 	return
 }
 
+// EqualsUpToPhase : Returns bool, true if the input matrix matches the current matrix once
+// a global phase e^(i phi) is removed, e.g. gate.U with different alpha values
+func (m Matrix) EqualsUpToPhase(input Matrix, eps ...float64) bool {
+	// count the number of rows and columns in both matrix
+	mRows, mColumns := m.Dimension()
+	inputRows, inputColumns := input.Dimension()
+	// if the columns or rows do not match return false
+	if mRows != inputRows || mColumns != inputColumns {
+		return false
+	}
+	// the phase that best aligns the two is the argument of their overlap
+	var overlap complex128
+	for i := 0; i < mRows; i++ {
+		for j := 0; j < mColumns; j++ {
+			overlap += cmplx.Conj(input[i][j]) * m[i][j]
+		}
+	}
+	// with no overlap the only way to match is for both to be (near) zero
+	if overlap == 0 {
+		return m.Equals(input, eps...)
+	}
+	return m.Equals(input.Multiply(overlap/complex(cmplx.Abs(overlap), 0)), eps...)
+}
+
+// ProcessFidelity : Returns |Tr(m^dagger input)|^2 / d^2 for two unitaries of dimension d,
+// 1 when they match up to a global phase and smaller the further apart they are, panics
+// if the two are not of the same square dimension
+func (m Matrix) ProcessFidelity(input Matrix) float64 {
+	// get the number of rows and columns
+	rows, columns := m.Dimension()
+	inputRows, inputColumns := input.Dimension()
+	if rows != columns || inputRows != rows || inputColumns != columns {
+		panic(fmt.Sprintf("matrix: ProcessFidelity of a %dx%d and a %dx%d matrix", rows, columns, inputRows, inputColumns))
+	}
+	// Tr(m^dagger input) is the sum of conj(m) * input over all components
+	var trace complex128
+	for i := 0; i < rows; i++ {
+		for j := 0; j < columns; j++ {
+			trace += cmplx.Conj(m[i][j]) * input[i][j]
+		}
+	}
+	abs := cmplx.Abs(trace) / float64(rows)
+	return abs * abs
+}
+
 // Conjugate : Returns the conjugate of the current matrix
 func (m Matrix) Conjugate() (mConj Matrix) {
 	// get the number of rows and columns
@@ -189,14 +237,14 @@ func (m Matrix) TensorProduct(input Matrix) (product Matrix) {
 		}
 	}
 
-	// for matrix in tmp matrix slice
-	for l := 0; l < len(tmp); l = l + mRows {
+	// for each row of matrices in tmp matrix slice
+	for l := 0; l < len(tmp); l = l + mColumns {
 		// for rows in each matrix
 		for j := 0; j < inputRows; j++ {
 			// create the new vector
 			vector := []complex128{}
-			// for each row in current matrix
-			for i := l; i < l+mRows; i++ {
+			// for each matrix along the row of the current matrix
+			for i := l; i < l+mColumns; i++ {
 				// for each component in row
 				for k := 0; k < inputColumns; k++ {
 					// append the value from temp > matrix > row > component
@@ -283,6 +331,41 @@ func (m Matrix) Trace() (sum complex128) {
 	return
 }
 
+// Determinant : Returns the determinant of a square matrix by Gaussian elimination
+func (m Matrix) Determinant() (det complex128) {
+	n := len(m)
+	// work on a copy, elimination changes the rows
+	a := make(Matrix, n)
+	for i := range a {
+		a[i] = append([]complex128{}, m[i]...)
+	}
+	det = 1
+	for i := 0; i < n; i++ {
+		// partial pivoting on the largest component in the column
+		pivot := i
+		for r := i + 1; r < n; r++ {
+			if cmplx.Abs(a[r][i]) > cmplx.Abs(a[pivot][i]) {
+				pivot = r
+			}
+		}
+		if a[pivot][i] == 0 {
+			return 0
+		}
+		if pivot != i {
+			a[i], a[pivot] = a[pivot], a[i]
+			det = -det
+		}
+		det *= a[i][i]
+		for r := i + 1; r < n; r++ {
+			f := a[r][i] / a[i][i]
+			for c := i; c < n; c++ {
+				a[r][c] -= f * a[i][c]
+			}
+		}
+	}
+	return
+}
+
 // TensorProductN : Returns the tensor product of a given matrix n times
 func TensorProductN(input Matrix, bit ...int) (product Matrix) {
 	product = input
@@ -297,7 +380,8 @@ func TensorProductN(input Matrix, bit ...int) (product Matrix) {
 	return
 }
 
-// TensorProduct : Returns the tensor product of a given matrix
+// TensorProduct : Returns the tensor product of a given matrix, the first matrix acts on the
+// most significant bits of the result (bit 0 in the order of package qubit)
 func TensorProduct(input ...Matrix) (product Matrix) {
 	// set the product to be the first matrix
 	product = input[0]
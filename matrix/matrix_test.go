@@ -0,0 +1,76 @@
+package matrix
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+func TestEqualsUpToPhase(t *testing.T) {
+	h := Matrix{{1 / math.Sqrt2, 1 / math.Sqrt2}, {1 / math.Sqrt2, -1 / math.Sqrt2}}
+	phase := cmplx.Exp(0.7i)
+	tests := []struct {
+		name  string
+		m     Matrix
+		input Matrix
+		want  bool
+	}{
+		{"identical", h, h, true},
+		{"global phase", h, h.Multiply(phase), true},
+		{"minus one", h, h.Multiply(-1), true},
+		{"relative phase", Matrix{{1, 0}, {0, 1}}, Matrix{{1, 0}, {0, 1i}}, false},
+		{"scaled", h, h.Multiply(2), false},
+		{"different gate", h, Matrix{{0, 1}, {1, 0}}, false},
+		{"orthogonal", Matrix{{1, 0}, {0, 0}}, Matrix{{0, 0}, {0, 1}}, false},
+		{"both zero", Matrix{{0, 0}, {0, 0}}, Matrix{{0, 0}, {0, 0}}, true},
+		{"different size", h, Matrix{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.m.EqualsUpToPhase(tt.input, 1e-12); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestProcessFidelity(t *testing.T) {
+	h := Matrix{{1 / math.Sqrt2, 1 / math.Sqrt2}, {1 / math.Sqrt2, -1 / math.Sqrt2}}
+	i := Matrix{{1, 0}, {0, 1}}
+	tests := []struct {
+		name     string
+		m, input Matrix
+		want     float64
+	}{
+		{"identical", h, h, 1},
+		{"global phase", h, h.Multiply(cmplx.Exp(2i)), 1},
+		{"I and X", i, Matrix{{0, 1}, {1, 0}}, 0},
+		{"I and Z", i, Matrix{{1, 0}, {0, -1}}, 0},
+		{"I and S", i, Matrix{{1, 0}, {0, 1i}}, 0.5},
+		{"I and H", i, h, 0},
+		{"I and T", i, Matrix{{1, 0}, {0, cmplx.Exp(math.Pi / 4 * 1i)}}, (2 + math.Sqrt2) / 4},
+	}
+	for _, tt := range tests {
+		if got := tt.m.ProcessFidelity(tt.input); math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestProcessFidelityPanicsOnMismatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		m, input Matrix
+	}{
+		{"different size", Matrix{{1, 0}, {0, 1}}, Matrix{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}},
+		{"not square", Matrix{{1, 0}}, Matrix{{1, 0}}},
+	}
+	for _, tt := range tests {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected a panic", tt.name)
+				}
+			}()
+			tt.m.ProcessFidelity(tt.input)
+		}()
+	}
+}
@@ -0,0 +1,527 @@
+package qec
+
+// matchingEdge : An edge of the graph given to maximumWeightMatching
+type matchingEdge struct {
+	i, j   int
+	weight int64
+}
+
+// maximumWeightMatching : Returns the mate of every vertex (or -1) in a maximum cardinality
+// matching of greatest total weight, by Edmonds' blossom algorithm with Galil's primal dual
+// bookkeeping, O(n^3). Vertices are labelled 0 (free), 1 (S, outer) or 2 (T, inner), blossoms
+// are numbered after the vertices, and edge k has endpoints 2k and 2k+1 so p^1 is the other end
+func maximumWeightMatching(edges []matchingEdge) []int {
+	if len(edges) == 0 {
+		return nil
+	}
+	nedge := len(edges)
+	nvertex := 0
+	var maxWeight int64
+	for _, e := range edges {
+		if e.i+1 > nvertex {
+			nvertex = e.i + 1
+		}
+		if e.j+1 > nvertex {
+			nvertex = e.j + 1
+		}
+		if e.weight > maxWeight {
+			maxWeight = e.weight
+		}
+	}
+	endpoint := make([]int, 2*nedge)
+	neighbend := make([][]int, nvertex)
+	for k, e := range edges {
+		endpoint[2*k], endpoint[2*k+1] = e.i, e.j
+		neighbend[e.i] = append(neighbend[e.i], 2*k+1)
+		neighbend[e.j] = append(neighbend[e.j], 2*k)
+	}
+	filled := func(n, v int) []int {
+		s := make([]int, n)
+		for i := range s {
+			s[i] = v
+		}
+		return s
+	}
+	mate := filled(nvertex, -1)
+	label := make([]int, 2*nvertex)
+	labelend := filled(2*nvertex, -1)
+	inblossom := make([]int, nvertex)
+	for i := range inblossom {
+		inblossom[i] = i
+	}
+	blossomparent := filled(2*nvertex, -1)
+	blossomchilds := make([][]int, 2*nvertex)
+	blossombase := filled(2*nvertex, -1)
+	for i := 0; i < nvertex; i++ {
+		blossombase[i] = i
+	}
+	blossomendps := make([][]int, 2*nvertex)
+	bestedge := filled(2*nvertex, -1)
+	blossombestedges := make([][]int, 2*nvertex)
+	unusedblossoms := []int{}
+	for b := nvertex; b < 2*nvertex; b++ {
+		unusedblossoms = append(unusedblossoms, b)
+	}
+	dualvar := make([]int64, 2*nvertex)
+	for i := 0; i < nvertex; i++ {
+		dualvar[i] = maxWeight
+	}
+	allowedge := make([]bool, nedge)
+	queue := []int{}
+
+	slack := func(k int) int64 {
+		e := edges[k]
+		return dualvar[e.i] + dualvar[e.j] - 2*e.weight
+	}
+	// at : Returns s[j] counting from the end for negative j
+	at := func(s []int, j int) int {
+		return s[(j%len(s)+len(s))%len(s)]
+	}
+	var leaves func(b int, visit func(v int))
+	leaves = func(b int, visit func(v int)) {
+		if b < nvertex {
+			visit(b)
+			return
+		}
+		for _, t := range blossomchilds[b] {
+			leaves(t, visit)
+		}
+	}
+
+	// assignLabel : Labels the top blossom of w with t reached through endpoint p, an S blossom
+	// joins the queue and a T blossom labels its mate S
+	var assignLabel func(w, t, p int)
+	assignLabel = func(w, t, p int) {
+		b := inblossom[w]
+		label[w], label[b] = t, t
+		labelend[w], labelend[b] = p, p
+		bestedge[w], bestedge[b] = -1, -1
+		if t == 1 {
+			leaves(b, func(v int) { queue = append(queue, v) })
+			return
+		}
+		base := blossombase[b]
+		assignLabel(endpoint[mate[base]], 1, mate[base]^1)
+	}
+
+	// scanBlossom : Returns the base of the new blossom closed by an edge between the S
+	// vertices v and w, or -1 if their trees differ and the edge gives an augmenting path
+	scanBlossom := func(v, w int) int {
+		path := []int{}
+		base := -1
+		for v != -1 || w != -1 {
+			b := inblossom[v]
+			if label[b]&4 != 0 {
+				base = blossombase[b]
+				break
+			}
+			path = append(path, b)
+			label[b] = 5
+			if labelend[b] == -1 {
+				v = -1
+			} else {
+				v = endpoint[labelend[b]]
+				b = inblossom[v]
+				v = endpoint[labelend[b]]
+			}
+			if w != -1 {
+				v, w = w, v
+			}
+		}
+		for _, b := range path {
+			label[b] = 1
+		}
+		return base
+	}
+
+	// addBlossom : Shrinks the odd cycle through edge k and the given base into a new S blossom
+	addBlossom := func(base, k int) {
+		v, w := edges[k].i, edges[k].j
+		bb, bv, bw := inblossom[base], inblossom[v], inblossom[w]
+		b := unusedblossoms[len(unusedblossoms)-1]
+		unusedblossoms = unusedblossoms[:len(unusedblossoms)-1]
+		blossombase[b] = base
+		blossomparent[b] = -1
+		blossomparent[bb] = b
+		path, endps := []int{}, []int{}
+		for bv != bb {
+			blossomparent[bv] = b
+			path = append(path, bv)
+			endps = append(endps, labelend[bv])
+			v = endpoint[labelend[bv]]
+			bv = inblossom[v]
+		}
+		path = append(path, bb)
+		for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+			path[i], path[j] = path[j], path[i]
+		}
+		for i, j := 0, len(endps)-1; i < j; i, j = i+1, j-1 {
+			endps[i], endps[j] = endps[j], endps[i]
+		}
+		endps = append(endps, 2*k)
+		for bw != bb {
+			blossomparent[bw] = b
+			path = append(path, bw)
+			endps = append(endps, labelend[bw]^1)
+			w = endpoint[labelend[bw]]
+			bw = inblossom[w]
+		}
+		blossomchilds[b], blossomendps[b] = path, endps
+		label[b] = 1
+		labelend[b] = labelend[bb]
+		dualvar[b] = 0
+		leaves(b, func(v int) {
+			if label[inblossom[v]] == 2 {
+				queue = append(queue, v)
+			}
+			inblossom[v] = b
+		})
+		// the least slack edge from the new blossom to each other S blossom
+		bestedgeto := filled(2*nvertex, -1)
+		for _, bv := range path {
+			lists := [][]int{}
+			if blossombestedges[bv] == nil {
+				leaves(bv, func(v int) {
+					list := []int{}
+					for _, p := range neighbend[v] {
+						list = append(list, p/2)
+					}
+					lists = append(lists, list)
+				})
+			} else {
+				lists = append(lists, blossombestedges[bv])
+			}
+			for _, list := range lists {
+				for _, k := range list {
+					j := edges[k].j
+					if inblossom[j] == b {
+						j = edges[k].i
+					}
+					bj := inblossom[j]
+					if bj != b && label[bj] == 1 && (bestedgeto[bj] == -1 || slack(k) < slack(bestedgeto[bj])) {
+						bestedgeto[bj] = k
+					}
+				}
+			}
+			blossombestedges[bv] = nil
+			bestedge[bv] = -1
+		}
+		best := []int{}
+		for _, k := range bestedgeto {
+			if k != -1 {
+				best = append(best, k)
+			}
+		}
+		blossombestedges[b] = best
+		bestedge[b] = -1
+		for _, k := range best {
+			if bestedge[b] == -1 || slack(k) < slack(bestedge[b]) {
+				bestedge[b] = k
+			}
+		}
+	}
+
+	// expandBlossom : Dissolves a blossom into its sub-blossoms, relabelling them when a T
+	// blossom is expanded in the middle of a stage
+	var expandBlossom func(b int, endstage bool)
+	expandBlossom = func(b int, endstage bool) {
+		for _, s := range blossomchilds[b] {
+			blossomparent[s] = -1
+			switch {
+			case s < nvertex:
+				inblossom[s] = s
+			case endstage && dualvar[s] == 0:
+				expandBlossom(s, endstage)
+			default:
+				leaves(s, func(v int) { inblossom[v] = s })
+			}
+		}
+		if !endstage && label[b] == 2 {
+			childs, endps := blossomchilds[b], blossomendps[b]
+			entrychild := inblossom[endpoint[labelend[b]^1]]
+			j := 0
+			for childs[j] != entrychild {
+				j++
+			}
+			var jstep, endptrick int
+			if j&1 != 0 {
+				j -= len(childs)
+				jstep, endptrick = 1, 0
+			} else {
+				jstep, endptrick = -1, 1
+			}
+			// relabel the path from the entry child to the base
+			p := labelend[b]
+			for j != 0 {
+				label[endpoint[p^1]] = 0
+				label[endpoint[at(endps, j-endptrick)^endptrick^1]] = 0
+				assignLabel(endpoint[p^1], 2, p)
+				allowedge[at(endps, j-endptrick)/2] = true
+				j += jstep
+				p = at(endps, j-endptrick) ^ endptrick
+				allowedge[p/2] = true
+				j += jstep
+			}
+			bv := at(childs, j)
+			label[endpoint[p^1]], label[bv] = 2, 2
+			labelend[endpoint[p^1]], labelend[bv] = p, p
+			bestedge[bv] = -1
+			j += jstep
+			// the rest of the children are free, unless a vertex was reached from outside
+			for at(childs, j) != entrychild {
+				bv = at(childs, j)
+				if label[bv] == 1 {
+					j += jstep
+					continue
+				}
+				reached := -1
+				leaves(bv, func(v int) {
+					if reached == -1 && label[v] != 0 {
+						reached = v
+					}
+				})
+				if reached != -1 {
+					label[reached] = 0
+					label[endpoint[mate[blossombase[bv]]]] = 0
+					assignLabel(reached, 2, labelend[reached])
+				}
+				j += jstep
+			}
+		}
+		label[b], labelend[b] = -1, -1
+		blossomchilds[b], blossomendps[b] = nil, nil
+		blossombase[b] = -1
+		blossombestedges[b] = nil
+		bestedge[b] = -1
+		unusedblossoms = append(unusedblossoms, b)
+	}
+
+	// augmentBlossom : Swaps matched and unmatched edges along the even path from vertex v to
+	// the base of blossom b, making v the new base
+	var augmentBlossom func(b, v int)
+	augmentBlossom = func(b, v int) {
+		t := v
+		for blossomparent[t] != b {
+			t = blossomparent[t]
+		}
+		if t >= nvertex {
+			augmentBlossom(t, v)
+		}
+		childs, endps := blossomchilds[b], blossomendps[b]
+		i := 0
+		for childs[i] != t {
+			i++
+		}
+		j := i
+		var jstep, endptrick int
+		if i&1 != 0 {
+			j -= len(childs)
+			jstep, endptrick = 1, 0
+		} else {
+			jstep, endptrick = -1, 1
+		}
+		for j != 0 {
+			j += jstep
+			t = at(childs, j)
+			p := at(endps, j-endptrick) ^ endptrick
+			if t >= nvertex {
+				augmentBlossom(t, endpoint[p])
+			}
+			j += jstep
+			t = at(childs, j)
+			if t >= nvertex {
+				augmentBlossom(t, endpoint[p^1])
+			}
+			mate[endpoint[p]] = p ^ 1
+			mate[endpoint[p^1]] = p
+		}
+		blossomchilds[b] = append(append([]int{}, childs[i:]...), childs[:i]...)
+		blossomendps[b] = append(append([]int{}, endps[i:]...), endps[:i]...)
+		blossombase[b] = blossombase[blossomchilds[b][0]]
+	}
+
+	// augmentMatching : Flips the augmenting path through edge k between two S vertices
+	augmentMatching := func(k int) {
+		for _, start := range [][2]int{{edges[k].i, 2*k + 1}, {edges[k].j, 2 * k}} {
+			s, p := start[0], start[1]
+			for {
+				bs := inblossom[s]
+				if bs >= nvertex {
+					augmentBlossom(bs, s)
+				}
+				mate[s] = p
+				if labelend[bs] == -1 {
+					break
+				}
+				t := endpoint[labelend[bs]]
+				bt := inblossom[t]
+				s = endpoint[labelend[bt]]
+				j := endpoint[labelend[bt]^1]
+				if bt >= nvertex {
+					augmentBlossom(bt, j)
+				}
+				mate[j] = labelend[bt]
+				p = labelend[bt] ^ 1
+			}
+		}
+	}
+
+	// each stage grows alternating trees from the free vertices until it augments the matching
+	for stage := 0; stage < nvertex; stage++ {
+		for i := range label {
+			label[i] = 0
+			bestedge[i] = -1
+		}
+		for b := nvertex; b < 2*nvertex; b++ {
+			blossombestedges[b] = nil
+		}
+		for k := range allowedge {
+			allowedge[k] = false
+		}
+		queue = queue[:0]
+		for v := 0; v < nvertex; v++ {
+			if mate[v] == -1 && label[inblossom[v]] == 0 {
+				assignLabel(v, 1, -1)
+			}
+		}
+		augmented := false
+		for {
+			for len(queue) > 0 && !augmented {
+				v := queue[len(queue)-1]
+				queue = queue[:len(queue)-1]
+				for _, p := range neighbend[v] {
+					k := p / 2
+					w := endpoint[p]
+					if inblossom[v] == inblossom[w] {
+						continue
+					}
+					var kslack int64
+					if !allowedge[k] {
+						kslack = slack(k)
+						if kslack <= 0 {
+							allowedge[k] = true
+						}
+					}
+					switch {
+					case allowedge[k] && label[inblossom[w]] == 0:
+						assignLabel(w, 2, p^1)
+					case allowedge[k] && label[inblossom[w]] == 1:
+						if base := scanBlossom(v, w); base >= 0 {
+							addBlossom(base, k)
+						} else {
+							augmentMatching(k)
+							augmented = true
+						}
+					case allowedge[k] && label[w] == 0:
+						label[w] = 2
+						labelend[w] = p ^ 1
+					case allowedge[k]:
+					case label[inblossom[w]] == 1:
+						b := inblossom[v]
+						if bestedge[b] == -1 || kslack < slack(bestedge[b]) {
+							bestedge[b] = k
+						}
+					case label[w] == 0:
+						if bestedge[w] == -1 || kslack < slack(bestedge[w]) {
+							bestedge[w] = k
+						}
+					}
+					if augmented {
+						break
+					}
+				}
+			}
+			if augmented {
+				break
+			}
+
+			// no tight edge is left, so change the duals by the least slack
+			deltatype := -1
+			var delta int64
+			deltaedge, deltablossom := -1, -1
+			for v := 0; v < nvertex; v++ {
+				if label[inblossom[v]] == 0 && bestedge[v] != -1 {
+					if d := slack(bestedge[v]); deltatype == -1 || d < delta {
+						delta, deltatype, deltaedge = d, 2, bestedge[v]
+					}
+				}
+			}
+			for b := 0; b < 2*nvertex; b++ {
+				if blossomparent[b] == -1 && label[b] == 1 && bestedge[b] != -1 {
+					if d := slack(bestedge[b]) / 2; deltatype == -1 || d < delta {
+						delta, deltatype, deltaedge = d, 3, bestedge[b]
+					}
+				}
+			}
+			for b := nvertex; b < 2*nvertex; b++ {
+				if blossombase[b] >= 0 && blossomparent[b] == -1 && label[b] == 2 && (deltatype == -1 || dualvar[b] < delta) {
+					delta, deltatype, deltablossom = dualvar[b], 4, b
+				}
+			}
+			if deltatype == -1 {
+				// no further improvement is possible, the matching has maximum cardinality
+				deltatype = 1
+				delta = 0
+				for v := 0; v < nvertex; v++ {
+					if v == 0 || dualvar[v] < delta {
+						delta = dualvar[v]
+					}
+				}
+				if delta < 0 {
+					delta = 0
+				}
+			}
+			for v := 0; v < nvertex; v++ {
+				switch label[inblossom[v]] {
+				case 1:
+					dualvar[v] -= delta
+				case 2:
+					dualvar[v] += delta
+				}
+			}
+			for b := nvertex; b < 2*nvertex; b++ {
+				if blossombase[b] >= 0 && blossomparent[b] == -1 {
+					switch label[b] {
+					case 1:
+						dualvar[b] += delta
+					case 2:
+						dualvar[b] -= delta
+					}
+				}
+			}
+			switch deltatype {
+			case 2:
+				allowedge[deltaedge] = true
+				i, j := edges[deltaedge].i, edges[deltaedge].j
+				if label[inblossom[i]] == 0 {
+					i = j
+				}
+				queue = append(queue, i)
+				continue
+			case 3:
+				allowedge[deltaedge] = true
+				queue = append(queue, edges[deltaedge].i)
+				continue
+			case 4:
+				expandBlossom(deltablossom, false)
+				continue
+			}
+			break
+		}
+		if !augmented {
+			break
+		}
+		// S blossoms with zero dual are expanded at the end of the stage
+		for b := nvertex; b < 2*nvertex; b++ {
+			if blossomparent[b] == -1 && blossombase[b] >= 0 && label[b] == 1 && dualvar[b] == 0 {
+				expandBlossom(b, true)
+			}
+		}
+	}
+	for v := range mate {
+		if mate[v] >= 0 {
+			mate[v] = endpoint[mate[v]]
+		}
+	}
+	return mate
+}
@@ -0,0 +1,68 @@
+package qec
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// bruteMatching : Returns the largest weight of a perfect matching among the unused vertices
+// by trying every partner of the first one, and false when there is none
+func bruteMatching(w [][]int64, used int) (int64, bool) {
+	n, i := len(w), 0
+	for i < n && used>>uint(i)&1 != 0 {
+		i++
+	}
+	if i == n {
+		return 0, true
+	}
+	best, found := int64(0), false
+	for j := i + 1; j < n; j++ {
+		if used>>uint(j)&1 != 0 || w[i][j] < 0 {
+			continue
+		}
+		if rest, ok := bruteMatching(w, used|1<<uint(i)|1<<uint(j)); ok && (!found || rest+w[i][j] > best) {
+			best, found = rest+w[i][j], true
+		}
+	}
+	return best, found
+}
+
+func TestMaximumWeightMatching(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 2000; trial++ {
+		// random graphs on up to 10 vertices, a missing edge having weight -1
+		n := 2 * (1 + rng.Intn(5))
+		w := make([][]int64, n)
+		for i := range w {
+			w[i] = make([]int64, n)
+		}
+		edges := []matchingEdge{}
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				if rng.Intn(3) == 0 {
+					w[i][j] = -1
+					continue
+				}
+				w[i][j] = int64(2 * (1 + rng.Intn(20)))
+				edges = append(edges, matchingEdge{i, j, w[i][j]})
+			}
+		}
+		want, ok := bruteMatching(w, 0)
+		if !ok {
+			continue
+		}
+		mate := maximumWeightMatching(edges)
+		var got int64
+		for i := 0; i < n; i++ {
+			if i >= len(mate) || mate[i] < 0 || mate[mate[i]] != i {
+				t.Fatalf("trial %d: %v is not a perfect matching", trial, mate)
+			}
+			if i < mate[i] {
+				got += w[i][mate[i]]
+			}
+		}
+		if got != want {
+			t.Fatalf("trial %d: matching of weight %d, want %d", trial, got, want)
+		}
+	}
+}
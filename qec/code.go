@@ -0,0 +1,214 @@
+// Package qec encodes a qubit into small stabilizer codes, extracts their syndromes, decodes
+// them and measures how the logical error rate follows the physical one under Pauli noise.
+// Larger repetition and surface codes run as memory experiments on a stabilizer Tableau and
+// are decoded by minimum weight perfect matching on a detector Graph.
+// Pauli strings are written as in package hamiltonian, letter i acting on qubit i
+package qec
+
+import (
+	"fmt"
+
+	"github.com/benluxford/qe/circuit"
+	"github.com/benluxford/qe/qubit"
+)
+
+// Code : A stabilizer code of one logical qubit on NumberOfBit physical qubits. Codewords are
+// the +1 eigenstates of every stabilizer, and LogicalX and LogicalZ act on them as X and Z.
+// Distance is the least weight of a Pauli acting on the codewords other than as the identity
+type Code struct {
+	Name        string
+	Distance    int
+	Stabilizers []string
+	LogicalX    string
+	LogicalZ    string
+	n           int
+	input       int
+	encoder     *circuit.Circuit
+	// the minimum weight correction of each syndrome, indexed by its value
+	table []pauli
+}
+
+// BitFlipCode : Returns the three qubit repetition code |0> -> |000>, |1> -> |111>, which
+// corrects a single X error. It has classical distance three against bit flips only, as a
+// quantum code it is [[3,1,1]]: a Z on any one qubit is a logical Z
+func BitFlipCode() *Code {
+	return css("bit flip", 1, []string{"ZZI", "IZZ"}, "XXX", "ZZZ")
+}
+
+// PhaseFlipCode : Returns the three qubit repetition code |0> -> |+++>, |1> -> |--->, which
+// corrects a single Z error. Like the bit flip code it is [[3,1,1]], an X on any one qubit
+// being a logical X
+func PhaseFlipCode() *Code {
+	encoder := circuit.New(3).CNOT(0, 1).CNOT(0, 2).H(0).H(1).H(2)
+	return build("phase flip", 1, []string{"XXI", "IXX"}, "ZZZ", "XXX", 0, encoder)
+}
+
+// ShorCode : Returns Shor's nine qubit code, the phase flip code with each qubit in a bit flip
+// code, which corrects any single qubit error
+func ShorCode() *Code {
+	encoder := circuit.New(9).CNOT(0, 3).CNOT(0, 6)
+	for _, b := range []int{0, 3, 6} {
+		encoder.H(b).CNOT(b, b+1).CNOT(b, b+2)
+	}
+	stabilizers := []string{
+		"ZZIIIIIII", "IZZIIIIII",
+		"IIIZZIIII", "IIIIZZIII",
+		"IIIIIIZZI", "IIIIIIIZZ",
+		"XXXXXXIII", "IIIXXXXXX",
+	}
+	return build("Shor", 3, stabilizers, "ZZZZZZZZZ", "XXXXXXXXX", 0, encoder)
+}
+
+// SteaneCode : Returns the Steane [[7,1,3]] code, X and Z checks from the parity checks of the
+// Hamming code, which corrects any single qubit error
+func SteaneCode() *Code {
+	stabilizers := []string{
+		"IIIXXXX", "IXXIIXX", "XIXIXIX",
+		"IIIZZZZ", "IZZIIZZ", "ZIZIZIZ",
+	}
+	return css("Steane", 3, stabilizers, "XXXXXXX", "ZZZZZZZ")
+}
+
+// SurfaceCode : Returns the distance three rotated surface code on a 3x3 grid of data qubits,
+// qubit 3r+c in row r and column c. X checks sit on the top and bottom edges, Z checks on the
+// left and right, LogicalX runs down the first column and LogicalZ along the first row
+func SurfaceCode() *Code {
+	stabilizers := []string{
+		// weight four plaquettes, alternating X and Z
+		"XXIXXIIII", "IZZIZZIII", "IIIZZIZZI", "IIIIXXIXX",
+		// weight two checks on the edges
+		"IXXIIIIII", "IIIIIIXXI", "ZIIZIIIII", "IIIIIZIIZ",
+	}
+	return css("surface", 3, stabilizers, "XIIXIIXII", "ZZZIIIIII")
+}
+
+// NumberOfBit : Returns the number of physical qubits of the Code
+func (c *Code) NumberOfBit() int {
+	return c.n
+}
+
+// Input : Returns the qubit the Encoder reads the state to encode from, the others start in |0>
+func (c *Code) Input() int {
+	return c.input
+}
+
+// Encoder : Returns a Circuit taking the state on Input and zeros elsewhere to its codeword
+func (c *Code) Encoder() *circuit.Circuit {
+	return c.encoder.Clone()
+}
+
+// Encode : Returns the codeword of a single qubit state
+func (c *Code) Encode(input *qubit.Qubit) *qubit.Qubit {
+	factors := []*qubit.Qubit{}
+	for i := 0; i < c.n; i++ {
+		if i == c.input {
+			factors = append(factors, input)
+			continue
+		}
+		factors = append(factors, qubit.Zero())
+	}
+	return c.encoder.Run(qubit.TensorProduct(factors...))
+}
+
+// css : Returns a CSS code whose LogicalX is X type and LogicalZ Z type, with the encoder
+// built from its X checks. In reduced row echelon form each X check has a pivot qubit no other
+// check touches, so the encoder puts the input on a qubit of LogicalX away from the pivots,
+// copies it along LogicalX, then for each check applies H on its pivot and CX from there to
+// the rest of the check
+func css(name string, distance int, stabilizers []string, logicalX, logicalZ string) *Code {
+	n := len(logicalX)
+	rows := []uint64{}
+	for _, s := range stabilizers {
+		if p := parse(s, n); p.z == 0 {
+			rows = append(rows, p.x)
+		}
+	}
+	pivots := []int{}
+	for col, r := 0, 0; col < n && r < len(rows); col++ {
+		bit := uint64(1) << uint(col)
+		found := -1
+		for i := r; i < len(rows); i++ {
+			if rows[i]&bit != 0 {
+				found = i
+				break
+			}
+		}
+		if found < 0 {
+			continue
+		}
+		rows[r], rows[found] = rows[found], rows[r]
+		for i := range rows {
+			if i != r && rows[i]&bit != 0 {
+				rows[i] ^= rows[r]
+			}
+		}
+		pivots = append(pivots, col)
+		r++
+	}
+	// LogicalX times checks with the pivots cleared
+	x := parse(logicalX, n).x
+	for i, p := range pivots {
+		if x>>uint(p)&1 != 0 {
+			x ^= rows[i]
+		}
+	}
+	input := 0
+	for x>>uint(input)&1 == 0 {
+		input++
+	}
+	encoder := circuit.New(n)
+	for q := 0; q < n; q++ {
+		if q != input && x>>uint(q)&1 != 0 {
+			encoder.CNOT(input, q)
+		}
+	}
+	for i, p := range pivots {
+		encoder.H(p)
+		for q := 0; q < n; q++ {
+			if q != p && rows[i]>>uint(q)&1 != 0 {
+				encoder.CNOT(p, q)
+			}
+		}
+	}
+	return build(name, distance, stabilizers, logicalX, logicalZ, input, encoder)
+}
+
+// build : Returns the Code with its decoding table, panics if the operators do not commute
+// as a code's must
+func build(name string, distance int, stabilizers []string, logicalX, logicalZ string, input int, encoder *circuit.Circuit) *Code {
+	n := len(logicalX)
+	c := &Code{
+		Name:        name,
+		Distance:    distance,
+		Stabilizers: stabilizers,
+		LogicalX:    logicalX,
+		LogicalZ:    logicalZ,
+		n:           n,
+		input:       input,
+		encoder:     encoder,
+	}
+	x, z := parse(logicalX, n), parse(logicalZ, n)
+	for _, s := range c.checks() {
+		if !s.commutes(x) || !s.commutes(z) {
+			panic(fmt.Sprintf("qec: %s logical operators do not commute with %s", name, s.format(n)))
+		}
+	}
+	if x.commutes(z) {
+		panic(fmt.Sprintf("qec: %s logical X and Z commute", name))
+	}
+	c.table = c.lookup()
+	return c
+}
+
+// checks : Returns the stabilizers as paulis
+func (c *Code) checks() (checks []pauli) {
+	for _, s := range c.Stabilizers {
+		checks = append(checks, parse(s, c.n))
+	}
+	return
+}
+
+// String : Returns the Code as e.g. "Steane [[7,1,3]]"
+func (c *Code) String() string {
+	return fmt.Sprintf("%s [[%d,1,%d]]", c.Name, c.n, c.Distance)
+}
@@ -0,0 +1,39 @@
+package qec
+
+import "testing"
+
+func TestDistance(t *testing.T) {
+	for _, c := range []*Code{BitFlipCode(), PhaseFlipCode(), ShorCode(), SteaneCode(), SurfaceCode()} {
+		// the stabilizer group, phases dropped
+		group := map[pauli]bool{{}: true}
+		for _, s := range c.checks() {
+			for g := range group {
+				group[g.times(s)] = true
+			}
+		}
+		// the lightest Pauli commuting with every stabilizer without being one of them
+		distance := c.n + 1
+		checks := c.checks()
+		for x := uint64(0); x < 1<<uint(c.n); x++ {
+			for z := uint64(0); z < 1<<uint(c.n); z++ {
+				p := pauli{x, z}
+				if group[p] || p.weight() >= distance {
+					continue
+				}
+				logical := true
+				for _, s := range checks {
+					logical = logical && s.commutes(p)
+				}
+				if logical {
+					distance = p.weight()
+				}
+			}
+		}
+		if c.Distance != distance {
+			t.Errorf("%v: distance %d, want %d", c, c.Distance, distance)
+		}
+	}
+	if got := BitFlipCode().String(); got != "bit flip [[3,1,1]]" {
+		t.Errorf("got %s, want bit flip [[3,1,1]]", got)
+	}
+}
@@ -0,0 +1,232 @@
+package qec
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Boundary : The detector standing for the boundary of a code, where a chain of errors can end
+// without a second detection event
+const Boundary = -1
+
+// precision : The scale at which path weights are rounded to integers for the matching
+const precision = 1 << 20
+
+// Graph : A detector graph, each edge an independent error flipping the one or two detectors
+// at its ends and the faults it carries, e.g. the data qubits it acts on or the logical
+// observables it flips. Weights are usually Weight of the error's probability
+type Graph struct {
+	detectors int
+	edges     []graphEdge
+	adjacent  [][]int
+}
+
+// graphEdge : An edge of a Graph, b being the boundary node for a boundary edge
+type graphEdge struct {
+	a, b   int
+	weight float64
+	faults []int
+}
+
+// NewGraph : Returns a Graph on the given number of detectors and no edges
+func NewGraph(detectors int) *Graph {
+	return &Graph{detectors: detectors, adjacent: make([][]int, detectors+1)}
+}
+
+// Weight : Returns the weight of an error with probability p, log((1-p)/p), so that the lightest
+// set of errors explaining a syndrome is the most likely one
+func Weight(p float64) float64 {
+	return math.Log((1 - p) / p)
+}
+
+// NumberOfDetectors : Returns the number of detectors of the Graph
+func (g *Graph) NumberOfDetectors() int {
+	return g.detectors
+}
+
+// NumberOfEdges : Returns the number of edges of the Graph
+func (g *Graph) NumberOfEdges() int {
+	return len(g.edges)
+}
+
+// AddEdge : Adds an error flipping detectors a and b, or only a when b is Boundary, and the
+// given faults. Panics on a detector out of range, a loop or a negative weight
+func (g *Graph) AddEdge(a, b int, weight float64, faults ...int) *Graph {
+	if a < 0 || a >= g.detectors || b < Boundary || b >= g.detectors || a == b {
+		panic(fmt.Sprintf("qec: no edge from detector %d to %d of %d detectors", a, b, g.detectors))
+	}
+	if weight < 0 || math.IsNaN(weight) {
+		panic(fmt.Sprintf("qec: edge weight %g is negative", weight))
+	}
+	if b == Boundary {
+		b = g.detectors
+	}
+	g.edges = append(g.edges, graphEdge{a, b, weight, append([]int{}, faults...)})
+	g.adjacent[a] = append(g.adjacent[a], len(g.edges)-1)
+	g.adjacent[b] = append(g.adjacent[b], len(g.edges)-1)
+	return g
+}
+
+// Decode : Returns the faults flipped an odd number of times by the most likely set of errors
+// with the given detection events, in increasing order. Each event is joined either to another
+// event or to the boundary by a shortest path, choosing the pairing of least total weight by a
+// minimum weight perfect matching. A detector listed twice cancels out. Returns an error if
+// an event can reach neither another event nor the boundary
+func (g *Graph) Decode(detections []int) ([]int, error) {
+	odd := map[int]bool{}
+	for _, e := range detections {
+		if e < 0 || e >= g.detectors {
+			return nil, fmt.Errorf("qec: detection event %d out of range for %d detectors", e, g.detectors)
+		}
+		odd[e] = !odd[e]
+	}
+	events := []int{}
+	for e, o := range odd {
+		if o {
+			events = append(events, e)
+		}
+	}
+	sort.Ints(events)
+	k := len(events)
+	if k == 0 {
+		return []int{}, nil
+	}
+	paths := make([]shortestPaths, k)
+	for i, e := range events {
+		paths[i] = g.dijkstra(e)
+	}
+
+	// vertex i is event i and vertex k+i its own copy of the boundary; the copies pair up at no
+	// cost, so any number of events can end on the boundary and a perfect matching exists
+	edges := []matchingEdge{}
+	distances := []float64{}
+	for i := 0; i < k; i++ {
+		for j := i + 1; j < k; j++ {
+			if d := paths[i].distance[events[j]]; !math.IsInf(d, 1) {
+				edges = append(edges, matchingEdge{i, j, 0})
+				distances = append(distances, d)
+			}
+		}
+		if d := paths[i].distance[g.detectors]; !math.IsInf(d, 1) {
+			edges = append(edges, matchingEdge{i, k + i, 0})
+			distances = append(distances, d)
+			for j := i + 1; j < k; j++ {
+				if !math.IsInf(paths[j].distance[g.detectors], 1) {
+					edges = append(edges, matchingEdge{k + i, k + j, 0})
+					distances = append(distances, 0)
+				}
+			}
+		}
+	}
+	// the matching maximizes weight, so each distance is taken from a constant above them all;
+	// weights are kept even so the dual variables stay integers
+	var most int64
+	for i, d := range distances {
+		edges[i].weight = int64(math.Round(d * precision))
+		if edges[i].weight > most {
+			most = edges[i].weight
+		}
+	}
+	for i := range edges {
+		edges[i].weight = 2 * (most + 1 - edges[i].weight)
+	}
+	mate := maximumWeightMatching(edges)
+
+	flipped := map[int]bool{}
+	for i := 0; i < k; i++ {
+		if i >= len(mate) || mate[i] < 0 {
+			return nil, fmt.Errorf("qec: detection event %d cannot be matched", events[i])
+		}
+		switch m := mate[i]; {
+		case m == k+i:
+			paths[i].flip(g, g.detectors, flipped)
+		case m < k && m > i:
+			paths[i].flip(g, events[m], flipped)
+		case m >= k:
+			return nil, fmt.Errorf("qec: detection event %d cannot be matched", events[i])
+		}
+	}
+	faults := []int{}
+	for f, odd := range flipped {
+		if odd {
+			faults = append(faults, f)
+		}
+	}
+	sort.Ints(faults)
+	return faults, nil
+}
+
+// shortestPaths : The distance to every node of a Graph from one detector, and the edge last
+// used to reach it
+type shortestPaths struct {
+	distance []float64
+	previous []int
+}
+
+// dijkstra : Returns the shortest paths from a detector. Paths do not pass through the
+// boundary, which would join two boundary edges into one chain
+func (g *Graph) dijkstra(source int) (s shortestPaths) {
+	s.distance = make([]float64, g.detectors+1)
+	s.previous = make([]int, g.detectors+1)
+	for i := range s.distance {
+		s.distance[i] = math.Inf(1)
+		s.previous[i] = -1
+	}
+	s.distance[source] = 0
+	queue := &nodeQueue{{source, 0}}
+	for queue.Len() > 0 {
+		n := heap.Pop(queue).(node)
+		if n.distance > s.distance[n.index] || n.index == g.detectors {
+			continue
+		}
+		for _, e := range g.adjacent[n.index] {
+			edge := g.edges[e]
+			next := edge.a
+			if next == n.index {
+				next = edge.b
+			}
+			if d := n.distance + edge.weight; d < s.distance[next] {
+				s.distance[next] = d
+				s.previous[next] = e
+				heap.Push(queue, node{next, d})
+			}
+		}
+	}
+	return
+}
+
+// flip : Toggles the faults of every edge on the shortest path to the target
+func (s shortestPaths) flip(g *Graph, target int, flipped map[int]bool) {
+	for e := s.previous[target]; e >= 0; e = s.previous[target] {
+		for _, f := range g.edges[e].faults {
+			flipped[f] = !flipped[f]
+		}
+		if g.edges[e].a == target {
+			target = g.edges[e].b
+		} else {
+			target = g.edges[e].a
+		}
+	}
+}
+
+// node : A node of a Graph waiting in the Dijkstra queue at its tentative distance
+type node struct {
+	index    int
+	distance float64
+}
+
+// nodeQueue : A heap of nodes, nearest first
+type nodeQueue []node
+
+func (q nodeQueue) Len() int            { return len(q) }
+func (q nodeQueue) Less(i, j int) bool  { return q[i].distance < q[j].distance }
+func (q nodeQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *nodeQueue) Push(x interface{}) { *q = append(*q, x.(node)) }
+func (q *nodeQueue) Pop() interface{} {
+	old := *q
+	n := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return n
+}
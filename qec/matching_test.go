@@ -0,0 +1,126 @@
+package qec
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	// a line of detectors with the boundary past both ends, faults being the data qubits
+	g := NewGraph(4).AddEdge(0, Boundary, 1, 0).AddEdge(0, 1, 1, 1).AddEdge(1, 2, 1, 2).
+		AddEdge(2, 3, 1, 3).AddEdge(3, Boundary, 1, 4)
+	tests := []struct {
+		detections []int
+		want       []int
+	}{
+		{nil, []int{}},
+		{[]int{0}, []int{0}},
+		{[]int{1, 2}, []int{2}},
+		{[]int{0, 3}, []int{0, 4}},
+		{[]int{1}, []int{0, 1}},
+		{[]int{0, 1, 2, 3}, []int{1, 3}},
+		{[]int{2, 2}, []int{}},
+	}
+	for _, tt := range tests {
+		got, err := g.Decode(tt.detections)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("Decode(%v) = %v, want %v", tt.detections, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("Decode(%v) = %v, want %v", tt.detections, got, tt.want)
+				break
+			}
+		}
+	}
+	if _, err := NewGraph(2).AddEdge(0, Boundary, 1).Decode([]int{1}); err == nil {
+		t.Error("expected an error for an isolated detection")
+	}
+}
+
+func TestDecodeMinimumWeight(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for trial := 0; trial < 300; trial++ {
+		// a random small graph, the fault of each edge being its own index
+		detectors := 2 + rng.Intn(4)
+		g := NewGraph(detectors)
+		for a := 0; a < detectors; a++ {
+			for b := Boundary; b < a; b++ {
+				if rng.Intn(2) == 0 {
+					g.AddEdge(a, b, float64(1+rng.Intn(9))+rng.Float64(), g.NumberOfEdges())
+				}
+			}
+		}
+		target := 0
+		detections := []int{}
+		for d := 0; d < detectors; d++ {
+			if rng.Intn(2) == 0 {
+				detections = append(detections, d)
+				target |= 1 << uint(d)
+			}
+		}
+		// the lightest set of edges whose ends, past the boundary, are the detections
+		parity := func(mask int) (flips int, weight float64) {
+			for i, e := range g.edges {
+				if mask>>uint(i)&1 == 1 {
+					flips ^= 1 << uint(e.a) & (1<<uint(detectors) - 1)
+					flips ^= 1 << uint(e.b) & (1<<uint(detectors) - 1)
+					weight += e.weight
+				}
+			}
+			return
+		}
+		best := math.Inf(1)
+		for mask := 0; mask < 1<<uint(g.NumberOfEdges()); mask++ {
+			if flips, weight := parity(mask); flips == target && weight < best {
+				best = weight
+			}
+		}
+		faults, err := g.Decode(detections)
+		if math.IsInf(best, 1) {
+			if err == nil {
+				t.Fatalf("trial %d: expected an error", trial)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("trial %d: %v", trial, err)
+		}
+		mask := 0
+		for _, f := range faults {
+			mask |= 1 << uint(f)
+		}
+		flips, weight := parity(mask)
+		if flips != target || math.Abs(weight-best) > 1e-4 {
+			t.Fatalf("trial %d: edges %v of weight %g explain %b, want %b of weight %g", trial, faults, weight, flips, target, best)
+		}
+	}
+}
+
+func BenchmarkDecode(b *testing.B) {
+	for _, d := range []int{3, 5, 7, 9, 11} {
+		m := SurfaceMemory(d, d, 0.01)
+		g := m.Graph()
+		// the same shots for every run, drawn before the timer starts
+		rng := rand.New(rand.NewSource(int64(d)))
+		shots := [][]int{}
+		for len(shots) < 64 {
+			if detections, _ := m.Sample(rng); len(detections) > 0 {
+				shots = append(shots, detections)
+			}
+		}
+		b.Run(fmt.Sprintf("d=%d", d), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := g.Decode(shots[i%len(shots)]); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
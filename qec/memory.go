@@ -0,0 +1,265 @@
+package qec
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/benluxford/qe/circuit"
+)
+
+// Memory : A memory experiment, holding logical |0> of a code with Z checks through rounds of
+// syndrome extraction under bit flip noise and then measuring every data qubit. Before each
+// round every data qubit flips with probability Physical, and so does every check result.
+// Detector r of a check compares its results in rounds r and r-1, the last comparing the
+// final data qubits with the last round, so each error lights at most two detectors and
+// the detector Graph has one edge per error
+type Memory struct {
+	Name     string
+	Distance int
+	Rounds   int
+	Physical float64
+	data     int
+	// the data qubits of each check, and those whose parity is the logical Z
+	checks     [][]int
+	observable []int
+	graph      *Graph
+}
+
+// RepetitionMemory : Returns a memory experiment of the distance d bit flip repetition code,
+// data qubits 0 to d-1 in a line with a ZZ check on each neighbouring pair
+func RepetitionMemory(distance, rounds int, p float64) *Memory {
+	checks := [][]int{}
+	for i := 0; i+1 < distance; i++ {
+		checks = append(checks, []int{i, i + 1})
+	}
+	return memory("repetition", distance, distance, checks, []int{0}, rounds, p)
+}
+
+// SurfaceMemory : Returns a memory experiment of the distance d rotated surface code, laid out
+// as SurfaceCode with data qubit dr+c in row r and column c. Only the Z checks, the plaquettes
+// with r+c odd and the weight two checks on the left and right edges, see bit flips, and the
+// logical Z runs along the first row
+func SurfaceMemory(distance, rounds int, p float64) *Memory {
+	d := distance
+	at := func(r, c int) int { return d*r + c }
+	checks := [][]int{}
+	for r := 0; r+1 < d; r++ {
+		if r%2 == 0 {
+			checks = append(checks, []int{at(r, 0), at(r+1, 0)})
+		}
+		for c := 0; c+1 < d; c++ {
+			if (r+c)%2 == 1 {
+				checks = append(checks, []int{at(r, c), at(r, c+1), at(r+1, c), at(r+1, c+1)})
+			}
+		}
+		if (r+d)%2 == 0 {
+			checks = append(checks, []int{at(r, d-1), at(r+1, d-1)})
+		}
+	}
+	observable := []int{}
+	for c := 0; c < d; c++ {
+		observable = append(observable, at(0, c))
+	}
+	return memory("surface", distance, d*d, checks, observable, rounds, p)
+}
+
+// memory : Returns the Memory with its detector Graph, an edge for each data qubit flip
+// before each round carrying fault 0 when it flips the logical Z, and one for each
+// measurement error joining a check's detectors in consecutive rounds
+func memory(name string, distance, data int, checks [][]int, observable []int, rounds int, p float64) *Memory {
+	if distance < 2 || rounds < 1 {
+		panic(fmt.Sprintf("qec: no %s memory of distance %d over %d rounds", name, distance, rounds))
+	}
+	m := &Memory{
+		Name:       name,
+		Distance:   distance,
+		Rounds:     rounds,
+		Physical:   p,
+		data:       data,
+		checks:     checks,
+		observable: observable,
+	}
+	s := len(checks)
+	m.graph = NewGraph(s * (rounds + 1))
+	weight := Weight(p)
+	in := make([][]int, data)
+	for i, check := range checks {
+		for _, q := range check {
+			in[q] = append(in[q], i)
+		}
+	}
+	logical := map[int]bool{}
+	for _, q := range observable {
+		logical[q] = true
+	}
+	for r := 0; r < rounds; r++ {
+		for q, touched := range in {
+			a, b := r*s+touched[0], Boundary
+			if len(touched) > 1 {
+				b = r*s + touched[1]
+			}
+			if logical[q] {
+				m.graph.AddEdge(a, b, weight, 0)
+			} else {
+				m.graph.AddEdge(a, b, weight)
+			}
+		}
+		for i := range checks {
+			m.graph.AddEdge(r*s+i, (r+1)*s+i, weight)
+		}
+	}
+	return m
+}
+
+// NumberOfBit : Returns the number of qubits of the experiment, the data qubits followed by an
+// ancilla for each check
+func (m *Memory) NumberOfBit() int {
+	return m.data + len(m.checks)
+}
+
+// Graph : Returns the detector Graph of the experiment, every error weighted Weight(Physical)
+func (m *Memory) Graph() *Graph {
+	return m.graph
+}
+
+// Circuit : Returns the experiment without noise. Check i of round r is measured into
+// classical bit r*len(checks)+i, and data qubit q into the bits after the last round
+func (m *Memory) Circuit() *circuit.Circuit {
+	return m.circuit(nil)
+}
+
+// circuit : Returns the experiment with bit flips drawn from the rng, or none when it is nil
+func (m *Memory) circuit(rng *rand.Rand) *circuit.Circuit {
+	s := len(m.checks)
+	out := circuit.New(m.NumberOfBit())
+	flip := func(q int) {
+		if rng != nil && rng.Float64() < m.Physical {
+			out.X(q)
+		}
+	}
+	for r := 0; r < m.Rounds; r++ {
+		for q := 0; q < m.data; q++ {
+			flip(q)
+		}
+		for i, check := range m.checks {
+			for _, q := range check {
+				out.CNOT(q, m.data+i)
+			}
+		}
+		for i := range m.checks {
+			flip(m.data + i)
+			out.Measure(m.data+i, r*s+i).Reset(m.data + i)
+		}
+		out.Barrier()
+	}
+	for q := 0; q < m.data; q++ {
+		out.Measure(q, m.Rounds*s+q)
+	}
+	return out
+}
+
+// Sample : Runs one shot of the experiment with noise on a Tableau, and returns the detectors
+// that fired and whether the logical Z read from the data qubits was flipped
+func (m *Memory) Sample(rng *rand.Rand) (detections []int, flipped bool) {
+	rng = seed(rng)
+	clbits, err := NewTableau(m.NumberOfBit()).Run(m.circuit(rng), rng)
+	if err != nil {
+		panic(err)
+	}
+	s := len(m.checks)
+	previous := make([]int, s)
+	for r := 0; r <= m.Rounds; r++ {
+		for i, check := range m.checks {
+			result := 0
+			if r < m.Rounds {
+				result = clbits[r*s+i]
+			} else {
+				for _, q := range check {
+					result ^= clbits[m.Rounds*s+q]
+				}
+			}
+			if result != previous[i] {
+				detections = append(detections, r*s+i)
+			}
+			previous[i] = result
+		}
+	}
+	for _, q := range m.observable {
+		if clbits[m.Rounds*s+q] == 1 {
+			flipped = !flipped
+		}
+	}
+	return
+}
+
+// String : Returns the Memory as e.g. "surface d=5, 5 rounds"
+func (m *Memory) String() string {
+	return fmt.Sprintf("%s d=%d, %d rounds", m.Name, m.Distance, m.Rounds)
+}
+
+// Benchmark : The logical error rate of a Memory decoded by matching, with the detection
+// events seen and the time taken to simulate and to decode the shots
+type Benchmark struct {
+	Point
+	Memory    string
+	Qubits    int
+	Detectors int
+	Events    int
+	Sampling  time.Duration
+	Decoding  time.Duration
+}
+
+// Run : Returns the Benchmark of the given number of shots, each sampled on a Tableau and
+// decoded with the Graph. A shot fails when the decoder's logical correction does not match
+// the flip of the logical Z. A nil rng is seeded from the time
+func (m *Memory) Run(shots int, rng *rand.Rand) Benchmark {
+	rng = seed(rng)
+	b := Benchmark{
+		Point:     Point{Physical: m.Physical, Trials: shots},
+		Memory:    m.String(),
+		Qubits:    m.NumberOfBit(),
+		Detectors: m.graph.NumberOfDetectors(),
+	}
+	for shot := 0; shot < shots; shot++ {
+		start := time.Now()
+		detections, flipped := m.Sample(rng)
+		b.Sampling += time.Since(start)
+		start = time.Now()
+		faults, err := m.graph.Decode(detections)
+		b.Decoding += time.Since(start)
+		if err != nil {
+			panic(err)
+		}
+		b.Events += len(detections)
+		if predicted := len(faults) == 1; predicted != flipped {
+			b.Failures++
+		}
+	}
+	b.Logical = float64(b.Failures) / float64(shots)
+	return b
+}
+
+// String : Returns the Benchmark as one row of BenchmarkTable
+func (b Benchmark) String() string {
+	per := func(d time.Duration) string {
+		return fmt.Sprintf("%.1fµs", float64(d.Microseconds())/float64(b.Trials))
+	}
+	return fmt.Sprintf("%-24s %6d %9d %8.4f %8.2f %10.6f ± %.6f %12s %12s",
+		b.Memory, b.Qubits, b.Detectors, b.Physical, float64(b.Events)/float64(b.Trials),
+		b.Logical, b.Uncertainty(), per(b.Sampling), per(b.Decoding))
+}
+
+// BenchmarkTable : Returns the benchmarks as a table, with the mean detection events and the
+// mean time to sample and to decode a shot
+func BenchmarkTable(benchmarks []Benchmark) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%-24s %6s %9s %8s %8s %21s %12s %12s\n",
+		"memory", "qubits", "detectors", "physical", "events", "logical", "sample/shot", "decode/shot"))
+	for _, bench := range benchmarks {
+		b.WriteString(bench.String())
+		b.WriteString("\n")
+	}
+	return b.String()
+}
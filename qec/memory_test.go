@@ -0,0 +1,38 @@
+package qec
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestMemoryNoiseless(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	memories := []*Memory{RepetitionMemory(3, 2, 0), RepetitionMemory(5, 3, 0), SurfaceMemory(3, 3, 0), SurfaceMemory(5, 2, 0)}
+	for _, m := range memories {
+		for shot := 0; shot < 20; shot++ {
+			if detections, flipped := m.Sample(rng); len(detections) != 0 || flipped {
+				t.Fatalf("%v: detections %v and flipped %v without noise", m, detections, flipped)
+			}
+		}
+	}
+}
+
+func TestMemoryCorrectsSingleErrors(t *testing.T) {
+	// every error the graph knows of on its own is explained by itself
+	for _, m := range []*Memory{RepetitionMemory(5, 3, 0.01), SurfaceMemory(3, 3, 0.01), SurfaceMemory(5, 2, 0.01)} {
+		g := m.Graph()
+		for _, e := range g.edges {
+			detections := []int{e.a}
+			if e.b != g.detectors {
+				detections = append(detections, e.b)
+			}
+			faults, err := g.Decode(detections)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(faults)%2 != len(e.faults)%2 {
+				t.Errorf("%v: error %v decoded to faults %v", m, e, faults)
+			}
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package qec
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Noise : A Pauli channel acting on each qubit independently, applying X, Y or Z with the
+// given probabilities and leaving the qubit alone otherwise
+type Noise struct {
+	X, Y, Z float64
+}
+
+// BitFlip : Returns the channel applying X with probability p
+func BitFlip(p float64) Noise {
+	return Noise{X: p}
+}
+
+// PhaseFlip : Returns the channel applying Z with probability p
+func PhaseFlip(p float64) Noise {
+	return Noise{Z: p}
+}
+
+// Depolarizing : Returns the channel applying each of X, Y and Z with probability p/3
+func Depolarizing(p float64) Noise {
+	return Noise{p / 3, p / 3, p / 3}
+}
+
+// Probability : Returns the probability of any error on a qubit, the physical error rate
+func (n Noise) Probability() float64 {
+	return n.X + n.Y + n.Z
+}
+
+// String : Returns the Noise as e.g. "X 0.01, Y 0, Z 0.01"
+func (n Noise) String() string {
+	return fmt.Sprintf("X %g, Y %g, Z %g", n.X, n.Y, n.Z)
+}
+
+// Sample : Returns a Pauli error on the given number of qubits drawn from the channel
+func (n Noise) Sample(bits int, rng *rand.Rand) string {
+	return n.sample(bits, rng).format(bits)
+}
+
+// sample : Returns a pauli drawn from the channel
+func (n Noise) sample(bits int, rng *rand.Rand) (p pauli) {
+	for q := 0; q < bits; q++ {
+		r := rng.Float64()
+		bit := uint64(1) << uint(q)
+		switch {
+		case r < n.X:
+			p.x |= bit
+		case r < n.X+n.Y:
+			p.x |= bit
+			p.z |= bit
+		case r < n.X+n.Y+n.Z:
+			p.z |= bit
+		}
+	}
+	return
+}
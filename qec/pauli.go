@@ -0,0 +1,58 @@
+package qec
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+// pauli : A Pauli string without its phase, bit i of x (z) is set when qubit i has an X (Z)
+// part, so Y sets both
+type pauli struct {
+	x, z uint64
+}
+
+// parse : Returns the pauli of a string of I, X, Y and Z on n qubits, panics otherwise
+func parse(s string, n int) (p pauli) {
+	if len(s) != n || strings.Trim(s, "IXYZ") != "" {
+		panic(fmt.Sprintf("qec: %q is not a Pauli string on %d qubits", s, n))
+	}
+	for i, r := range s {
+		switch r {
+		case 'X':
+			p.x |= 1 << uint(i)
+		case 'Y':
+			p.x |= 1 << uint(i)
+			p.z |= 1 << uint(i)
+		case 'Z':
+			p.z |= 1 << uint(i)
+		}
+	}
+	return
+}
+
+// format : Returns the pauli as a string on n qubits
+func (p pauli) format(n int) string {
+	s := make([]byte, n)
+	for i := range s {
+		x, z := p.x>>uint(i)&1, p.z>>uint(i)&1
+		s[i] = "IZXY"[x<<1|z]
+	}
+	return string(s)
+}
+
+// times : Returns the product of two paulis, up to a phase
+func (p pauli) times(q pauli) pauli {
+	return pauli{p.x ^ q.x, p.z ^ q.z}
+}
+
+// commutes : Returns true if the two paulis commute, they anticommute when an odd number of
+// qubits hold different non identity letters
+func (p pauli) commutes(q pauli) bool {
+	return bits.OnesCount64(p.x&q.z^p.z&q.x)%2 == 0
+}
+
+// weight : Returns the number of qubits the pauli acts on
+func (p pauli) weight() int {
+	return bits.OnesCount64(p.x | p.z)
+}
@@ -0,0 +1,137 @@
+package qec
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/benluxford/qe/circuit"
+)
+
+// Point : The logical error rate of a Code measured at one physical error rate
+type Point struct {
+	Physical float64
+	Logical  float64
+	Trials   int
+	Failures int
+}
+
+// Uncertainty : Returns the standard error of the logical error rate
+func (p Point) Uncertainty() float64 {
+	return math.Sqrt(p.Logical * (1 - p.Logical) / float64(p.Trials))
+}
+
+// String : Returns the Point as e.g. "physical 0.0100 logical 0.000300 ± 0.000055 (3/10000)"
+func (p Point) String() string {
+	return fmt.Sprintf("physical %.4f logical %.6f ± %.6f (%d/%d)", p.Physical, p.Logical, p.Uncertainty(), p.Failures, p.Trials)
+}
+
+// LogicalErrorRate : Returns the fraction of trials in which an error drawn from the noise on
+// every data qubit, followed by perfect syndrome extraction and the lowest weight correction,
+// leaves a logical error. Errors are tracked as Pauli strings rather than states, so this is
+// fast enough for many trials. A nil rng is seeded from the time
+func (c *Code) LogicalErrorRate(noise Noise, trials int, rng *rand.Rand) Point {
+	rng = seed(rng)
+	point := Point{Physical: noise.Probability(), Trials: trials}
+	for t := 0; t < trials; t++ {
+		e := noise.sample(c.n, rng)
+		correction := c.table[value(c.syndrome(e))]
+		if c.logical(e.times(correction)) {
+			point.Failures++
+		}
+	}
+	point.Logical = float64(point.Failures) / float64(trials)
+	return point
+}
+
+// Simulate : Returns the same measure as LogicalErrorRate, but with each trial run on the state
+// vector simulator: the error drawn from the noise is applied to the encoded state, the
+// Correction circuit measures the syndrome and applies its correction, and the encoding is
+// undone. A trial fails if a logical |0> or a logical |+> comes back changed. A nil rng is
+// seeded from the time
+func (c *Code) Simulate(noise Noise, trials int, rng *rand.Rand) Point {
+	rng = seed(rng)
+	correction := c.Correction()
+	point := Point{Physical: noise.Probability(), Trials: trials}
+	for t := 0; t < trials; t++ {
+		e := noise.sample(c.n, rng)
+		for _, plus := range []bool{false, true} {
+			counts := c.cycle(e, correction, plus).Execute(nil, 1, rng).Counts()
+			if failed(counts) {
+				point.Failures++
+				break
+			}
+		}
+	}
+	point.Logical = float64(point.Failures) / float64(trials)
+	return point
+}
+
+// Sweep : Returns the LogicalErrorRate at each physical error rate, the noise model giving
+// the channel of each rate, e.g. Depolarizing
+func (c *Code) Sweep(model func(p float64) Noise, physical []float64, trials int, rng *rand.Rand) (points []Point) {
+	rng = seed(rng)
+	for _, p := range physical {
+		points = append(points, c.LogicalErrorRate(model(p), trials, rng))
+	}
+	return
+}
+
+// cycle : Returns a Circuit preparing logical |0> (or |+>), applying the error, correcting it
+// and undoing the encoding, then measuring the input qubit into the classical bit after the
+// syndrome. The encoders only use H and CX, so they are undone by running them backwards
+func (c *Code) cycle(e pauli, correction *circuit.Circuit, plus bool) *circuit.Circuit {
+	out := circuit.New(c.n + 1)
+	if plus {
+		out.H(c.input)
+	}
+	out.Compose(c.encoder)
+	apply(out, e, c.n)
+	out.Compose(correction)
+	ops := c.encoder.Ops()
+	for i := len(ops) - 1; i >= 0; i-- {
+		out.Append(ops[i])
+	}
+	if plus {
+		out.H(c.input)
+	}
+	return out.Measure(c.input, len(c.Stabilizers))
+}
+
+// failed : Returns true if any shot measured the logical qubit, the last classical bit, as one
+func failed(counts map[string]int) bool {
+	for bits := range counts {
+		if bits[len(bits)-1] == '1' {
+			return true
+		}
+	}
+	return false
+}
+
+// seed : Returns the rng, or a new one seeded from the time when it is nil
+func seed(rng *rand.Rand) *rand.Rand {
+	if rng == nil {
+		return rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return rng
+}
+
+// Table : Returns the points of one or more sweeps as a table with a column per code
+func Table(codes []*Code, points [][]Point) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%-10s", "physical"))
+	for _, c := range codes {
+		b.WriteString(fmt.Sprintf(" %22s", c.String()))
+	}
+	b.WriteString("\n")
+	for i := range points[0] {
+		b.WriteString(fmt.Sprintf("%-10.4f", points[0][i].Physical))
+		for j := range codes {
+			b.WriteString(fmt.Sprintf(" %22s", fmt.Sprintf("%.6f ± %.6f", points[j][i].Logical, points[j][i].Uncertainty())))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
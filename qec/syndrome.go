@@ -0,0 +1,138 @@
+package qec
+
+import (
+	"github.com/benluxford/qe/circuit"
+)
+
+// Syndrome : Returns the syndrome of a Pauli error, bit i is one when the error anticommutes
+// with stabilizer i and measuring it would give -1
+func (c *Code) Syndrome(fault string) []int {
+	return c.syndrome(parse(fault, c.n))
+}
+
+// syndrome : Returns the syndrome bits of a pauli
+func (c *Code) syndrome(p pauli) (syndrome []int) {
+	for _, s := range c.checks() {
+		bit := 0
+		if !s.commutes(p) {
+			bit = 1
+		}
+		syndrome = append(syndrome, bit)
+	}
+	return
+}
+
+// Decode : Returns the lowest weight Pauli correction with the given syndrome, the identity
+// when the syndrome is all zeros
+func (c *Code) Decode(syndrome []int) string {
+	return c.table[value(syndrome)].format(c.n)
+}
+
+// LogicalError : Returns true if the correction leaves the error as a logical operator rather
+// than a stabilizer, so the encoded state is changed
+func (c *Code) LogicalError(fault, correction string) bool {
+	return c.logical(parse(fault, c.n).times(parse(correction, c.n)))
+}
+
+// logical : Returns true if a residual error with no syndrome acts on the logical qubit
+func (c *Code) logical(residual pauli) bool {
+	return !residual.commutes(parse(c.LogicalX, c.n)) || !residual.commutes(parse(c.LogicalZ, c.n))
+}
+
+// SyndromeCircuit : Returns a Circuit on the data qubits and one ancilla, qubit
+// NumberOfBit, measuring stabilizer i into classical bit i. Each stabilizer rotates its data
+// qubits so it reads as Z, gathers their parity onto the ancilla with CX, rotates back, then
+// measures and resets the ancilla for the next
+func (c *Code) SyndromeCircuit() *circuit.Circuit {
+	ancilla := c.n
+	out := circuit.New(c.n + 1)
+	for i, s := range c.Stabilizers {
+		for q, p := range s {
+			switch p {
+			case 'X':
+				out.H(q).CNOT(q, ancilla).H(q)
+			case 'Y':
+				out.Sdg(q).H(q).CNOT(q, ancilla).H(q).S(q)
+			case 'Z':
+				out.CNOT(q, ancilla)
+			}
+		}
+		out.Measure(ancilla, i).Reset(ancilla)
+	}
+	return out
+}
+
+// Correction : Returns the SyndromeCircuit followed by the correction of every non zero
+// syndrome, each conditioned on the syndrome bits holding it
+func (c *Code) Correction() *circuit.Circuit {
+	out := c.SyndromeCircuit()
+	clbits := make([]int, len(c.Stabilizers))
+	for i := range clbits {
+		clbits[i] = i
+	}
+	for v, correction := range c.table {
+		if correction.weight() == 0 {
+			continue
+		}
+		body := circuit.New(c.n + 1)
+		apply(body, correction, c.n)
+		// syndrome bit 0 is the least significant bit of the table index, and the most
+		// significant bit of a condition
+		out.If(clbits, reverse(v, len(clbits)), body)
+	}
+	return out
+}
+
+// lookup : Returns the lowest weight pauli of every syndrome, found by trying every pauli on
+// the code's qubits, ties going to the first found
+func (c *Code) lookup() []pauli {
+	checks := c.checks()
+	table := make([]pauli, 1<<uint(len(checks)))
+	found := make([]bool, len(table))
+	all := uint64(1)<<uint(c.n) - 1
+	for x := uint64(0); x <= all; x++ {
+		for z := uint64(0); z <= all; z++ {
+			p := pauli{x, z}
+			v := 0
+			for i, s := range checks {
+				if !s.commutes(p) {
+					v |= 1 << uint(i)
+				}
+			}
+			if !found[v] || p.weight() < table[v].weight() {
+				table[v], found[v] = p, true
+			}
+		}
+	}
+	return table
+}
+
+// value : Returns the table index of a syndrome, bit i of the index being syndrome bit i
+func value(syndrome []int) (v int) {
+	for i, b := range syndrome {
+		v |= b << uint(i)
+	}
+	return
+}
+
+// reverse : Returns the value with its lowest n bits in reverse order
+func reverse(v, n int) (r int) {
+	for i := 0; i < n; i++ {
+		r = r<<1 | v>>uint(i)&1
+	}
+	return
+}
+
+// apply : Appends the gates of a pauli on the first n qubits
+func apply(out *circuit.Circuit, p pauli, n int) {
+	for q, r := range p.format(n) {
+		switch r {
+		case 'X':
+			out.X(q)
+		case 'Y':
+			out.Y(q)
+		case 'Z':
+			out.Z(q)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package qec
+
+import (
+	"fmt"
+	"math/bits"
+	"math/rand"
+
+	"github.com/benluxford/qe/circuit"
+)
+
+// Tableau : A stabilizer state of n qubits in the form of Aaronson and Gottesman, "Improved
+// simulation of stabilizer circuits" (2004). Rows 0 to n-1 are destabilizers, rows n to 2n-1
+// the stabilizers, each a Pauli string with X and Z parts packed into bits and a sign, and row
+// 2n is scratch space. Clifford gates and measurements cost O(n) and O(n^2) rather than the
+// 2^n of a state vector, so circuits of hundreds of qubits run quickly
+type Tableau struct {
+	n     int
+	words int
+	x, z  [][]uint64
+	r     []uint8
+}
+
+// NewTableau : Returns the Tableau of |0...0>, stabilized by Z on every qubit
+func NewTableau(n int) *Tableau {
+	t := &Tableau{n: n, words: (n + 63) / 64}
+	rows := 2*n + 1
+	t.x, t.z, t.r = make([][]uint64, rows), make([][]uint64, rows), make([]uint8, rows)
+	for i := range t.x {
+		t.x[i] = make([]uint64, t.words)
+		t.z[i] = make([]uint64, t.words)
+	}
+	for q := 0; q < n; q++ {
+		t.x[q][q/64] |= 1 << uint(q%64)
+		t.z[n+q][q/64] |= 1 << uint(q%64)
+	}
+	return t
+}
+
+// NumberOfBit : Returns the number of qubits of the Tableau
+func (t *Tableau) NumberOfBit() int {
+	return t.n
+}
+
+// H : Applies a Hadamard to the qubit, exchanging its X and Z
+func (t *Tableau) H(q int) *Tableau {
+	w, m := q/64, uint64(1)<<uint(q%64)
+	for i := 0; i < 2*t.n; i++ {
+		x, z := t.x[i][w]&m, t.z[i][w]&m
+		if x != 0 && z != 0 {
+			t.r[i] ^= 1
+		}
+		t.x[i][w] ^= x ^ z
+		t.z[i][w] ^= x ^ z
+	}
+	return t
+}
+
+// S : Applies an S gate to the qubit, taking X to Y
+func (t *Tableau) S(q int) *Tableau {
+	w, m := q/64, uint64(1)<<uint(q%64)
+	for i := 0; i < 2*t.n; i++ {
+		x, z := t.x[i][w]&m, t.z[i][w]&m
+		if x != 0 && z != 0 {
+			t.r[i] ^= 1
+		}
+		t.z[i][w] ^= x
+	}
+	return t
+}
+
+// Sdg : Applies the inverse of S to the qubit
+func (t *Tableau) Sdg(q int) *Tableau {
+	return t.S(q).Z(q)
+}
+
+// X : Applies a Pauli X to the qubit, negating the rows with Z or Y on it
+func (t *Tableau) X(q int) *Tableau {
+	return t.pauli(q, t.z)
+}
+
+// Z : Applies a Pauli Z to the qubit, negating the rows with X or Y on it
+func (t *Tableau) Z(q int) *Tableau {
+	return t.pauli(q, t.x)
+}
+
+// Y : Applies a Pauli Y to the qubit
+func (t *Tableau) Y(q int) *Tableau {
+	return t.X(q).Z(q)
+}
+
+// pauli : Negates every row with a one in the given part for the qubit
+func (t *Tableau) pauli(q int, part [][]uint64) *Tableau {
+	w, m := q/64, uint64(1)<<uint(q%64)
+	for i := 0; i < 2*t.n; i++ {
+		if part[i][w]&m != 0 {
+			t.r[i] ^= 1
+		}
+	}
+	return t
+}
+
+// CNOT : Applies an X on target controlled by control
+func (t *Tableau) CNOT(control, target int) *Tableau {
+	wc, mc := control/64, uint64(1)<<uint(control%64)
+	wt, mt := target/64, uint64(1)<<uint(target%64)
+	for i := 0; i < 2*t.n; i++ {
+		xc, zc := t.x[i][wc]&mc != 0, t.z[i][wc]&mc != 0
+		xt, zt := t.x[i][wt]&mt != 0, t.z[i][wt]&mt != 0
+		if xc && zt && xt == zc {
+			t.r[i] ^= 1
+		}
+		if xc {
+			t.x[i][wt] ^= mt
+		}
+		if zt {
+			t.z[i][wc] ^= mc
+		}
+	}
+	return t
+}
+
+// CZ : Applies a Z on target controlled by control
+func (t *Tableau) CZ(control, target int) *Tableau {
+	return t.H(target).CNOT(control, target).H(target)
+}
+
+// Swap : Exchanges qubits a and b
+func (t *Tableau) Swap(a, b int) *Tableau {
+	return t.CNOT(a, b).CNOT(b, a).CNOT(a, b)
+}
+
+// Measure : Returns the result of measuring the qubit in the computational basis, collapsing
+// the state. The result is random, drawn from rng, only when a stabilizer anticommutes with Z
+// on the qubit
+func (t *Tableau) Measure(q int, rng *rand.Rand) int {
+	w, m := q/64, uint64(1)<<uint(q%64)
+	n := t.n
+	p := -1
+	for i := n; i < 2*n; i++ {
+		if t.x[i][w]&m != 0 {
+			p = i
+			break
+		}
+	}
+	if p >= 0 {
+		for i := 0; i < 2*n; i++ {
+			if i != p && t.x[i][w]&m != 0 {
+				t.rowsum(i, p)
+			}
+		}
+		// the old stabilizer becomes a destabilizer and Z on the qubit, with the result as its
+		// sign, takes its place
+		copy(t.x[p-n], t.x[p])
+		copy(t.z[p-n], t.z[p])
+		t.r[p-n] = t.r[p]
+		for j := 0; j < t.words; j++ {
+			t.x[p][j], t.z[p][j] = 0, 0
+		}
+		t.z[p][w] = m
+		t.r[p] = uint8(rng.Intn(2))
+		return int(t.r[p])
+	}
+	// Z on the qubit is a product of stabilizers, gathered in the scratch row for its sign
+	s := 2 * n
+	for j := 0; j < t.words; j++ {
+		t.x[s][j], t.z[s][j] = 0, 0
+	}
+	t.r[s] = 0
+	for i := 0; i < n; i++ {
+		if t.x[i][w]&m != 0 {
+			t.rowsum(s, i+n)
+		}
+	}
+	return int(t.r[s])
+}
+
+// Reset : Returns the qubit to |0>, measuring it and flipping it back when the result was one
+func (t *Tableau) Reset(q int, rng *rand.Rand) *Tableau {
+	if t.Measure(q, rng) == 1 {
+		t.X(q)
+	}
+	return t
+}
+
+// rowsum : Sets row h to the product of rows i and h, tracking the sign. The phase of each
+// single qubit product is +i, -i or 1, counted over a word at a time from which of X, Y and Z
+// meet
+func (t *Tableau) rowsum(h, i int) {
+	phase := 2*int(t.r[h]) + 2*int(t.r[i])
+	for j := 0; j < t.words; j++ {
+		x1, z1, x2, z2 := t.x[i][j], t.z[i][j], t.x[h][j], t.z[h][j]
+		// XY, YZ and ZX multiply to +i, and the reverse orders to -i
+		plus := (x1 & z1 & z2 &^ x2) | (x1 &^ z1 & x2 & z2) | (z1 &^ x1 & x2 &^ z2)
+		minus := (x1 & z1 & x2 &^ z2) | (x1 &^ z1 & z2 &^ x2) | (z1 &^ x1 & x2 & z2)
+		phase += bits.OnesCount64(plus) - bits.OnesCount64(minus)
+		t.x[h][j] ^= x1
+		t.z[h][j] ^= z1
+	}
+	t.r[h] = 0
+	if (phase%4+4)%4 == 2 {
+		t.r[h] = 1
+	}
+}
+
+// Run : Runs a Clifford circuit on the Tableau and returns its classical bits. Supports H, S,
+// Sdg and the Paulis, CX, CZ and Swap, measurements, resets, barriers and conditioned
+// operations, and returns an error on any other gate
+func (t *Tableau) Run(c *circuit.Circuit, rng *rand.Rand) ([]int, error) {
+	if c.NumberOfBit() > t.n {
+		return nil, fmt.Errorf("qec: circuit of %d qubits on a tableau of %d", c.NumberOfBit(), t.n)
+	}
+	rng = seed(rng)
+	clbits := make([]int, c.NumberOfClbit())
+	for _, o := range c.Ops() {
+		if o.Condition != nil && !o.Condition.Holds(clbits) {
+			continue
+		}
+		// the name is only trusted when the matrix is the gate it names
+		if o.Matrix != nil && !o.Standard() {
+			return nil, fmt.Errorf("qec: %s is not a supported Clifford gate", o.Label())
+		}
+		q := o.Qubits()
+		switch o.Label() {
+		case "Barrier", "I":
+		case "Measure":
+			clbits[o.Clbits[0]] = t.Measure(q[0], rng)
+		case "Reset":
+			t.Reset(q[0], rng)
+		case "H":
+			t.H(q[0])
+		case "S":
+			t.S(q[0])
+		case "Sdg":
+			t.Sdg(q[0])
+		case "X":
+			t.X(q[0])
+		case "Y":
+			t.Y(q[0])
+		case "Z":
+			t.Z(q[0])
+		case "CX":
+			t.CNOT(q[0], q[1])
+		case "CZ":
+			t.CZ(q[0], q[1])
+		case "Swap":
+			t.Swap(q[0], q[1])
+		default:
+			return nil, fmt.Errorf("qec: %s is not a supported Clifford gate", o.Label())
+		}
+	}
+	return clbits, nil
+}
@@ -0,0 +1,91 @@
+package qec
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/benluxford/qe/circuit"
+	"github.com/benluxford/qe/gate"
+	"github.com/benluxford/qe/qubit"
+)
+
+func TestTableauAgainstStateVector(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	for trial := 0; trial < 200; trial++ {
+		n := 1 + rng.Intn(4)
+		c := circuit.New(n)
+		for g := 0; g < 15; g++ {
+			q, p := rng.Intn(n), rng.Intn(n)
+			switch k := rng.Intn(9); {
+			case k == 0:
+				c.H(q)
+			case k == 1:
+				c.S(q)
+			case k == 2:
+				c.Sdg(q)
+			case k == 3:
+				c.X(q)
+			case k == 4:
+				c.Y(q)
+			case k == 5:
+				c.Z(q)
+			case q == p:
+			case k == 6:
+				c.CNOT(q, p)
+			case k == 7:
+				c.CZ(q, p)
+			default:
+				c.Swap(q, p)
+			}
+		}
+		probability := c.Run(qubit.Zero(n)).Probability()
+		// a stabilizer state gives every outcome it can give the same probability
+		support := 0
+		for _, x := range probability {
+			if x > 1e-9 {
+				support++
+			}
+		}
+		c.MeasureAll()
+		seen := map[int]bool{}
+		for shot := 0; shot < 200; shot++ {
+			bits, err := NewTableau(n).Run(c, rng)
+			if err != nil {
+				t.Fatal(err)
+			}
+			index := 0
+			for _, b := range bits {
+				index = index<<1 | b
+			}
+			if probability[index] < 1e-9 {
+				t.Fatalf("trial %d: %v measured %v, which has probability 0", trial, c.Ops(), bits)
+			}
+			if p := 1 / float64(support); probability[index] < p-1e-9 || probability[index] > p+1e-9 {
+				t.Fatalf("trial %d: outcome %v has probability %g, want %g", trial, bits, probability[index], p)
+			}
+			seen[index] = true
+		}
+		if len(seen) != support {
+			t.Fatalf("trial %d: %v gave %d outcomes, want %d", trial, c.Ops(), len(seen), support)
+		}
+	}
+}
+
+func TestTableauRejectsNonClifford(t *testing.T) {
+	tests := []struct {
+		name  string
+		input *circuit.Circuit
+	}{
+		{"T", circuit.New(1).T(0)},
+		{"RX", circuit.New(1).RX(0, 0.3)},
+		{"Toffoli", circuit.New(3).Toffoli(0, 1, 2)},
+		{"H holding T", circuit.New(1).Gate("H", gate.T(), 0)},
+		{"X holding RX", circuit.New(1).Gate("X", gate.RX(0.3), 0)},
+		{"CX holding CRX", circuit.New(2).Append(circuit.Op{Name: "X", Controls: []int{0}, Targets: []int{1}, Matrix: gate.RX(0.3)})},
+	}
+	for _, tt := range tests {
+		if _, err := NewTableau(3).Run(tt.input, rand.New(rand.NewSource(1))); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
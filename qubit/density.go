@@ -0,0 +1,128 @@
+package qubit
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+
+	"github.com/benluxford/qe/matrix"
+)
+
+// DensityMatrix : Returns the density matrix |q><q| of the Qubit
+func (q *Qubit) DensityMatrix() matrix.Matrix {
+	dim := len(q.v)
+	rho := make(matrix.Matrix, dim)
+	for i := range rho {
+		rho[i] = make([]complex128, dim)
+		for j := range rho[i] {
+			rho[i][j] = q.v[i] * cmplx.Conj(q.v[j])
+		}
+	}
+	return rho
+}
+
+// ReducedDensityMatrix : Returns the density matrix of the given bits with every other bit
+// traced out, bit[0] becomes the most significant bit of the result
+func (q *Qubit) ReducedDensityMatrix(bit ...int) matrix.Matrix {
+	n := q.NumberOfBit()
+	kept, rest := split(n, bit)
+	dim := 1 << uint(len(bit))
+	rho := make(matrix.Matrix, dim)
+	for i := range rho {
+		rho[i] = make([]complex128, dim)
+	}
+	// sum |a, e><b, e| over every state e of the traced out bits
+	for e := 0; e < 1<<uint(len(rest)); e++ {
+		env := place(n, rest, e)
+		for a := 0; a < dim; a++ {
+			va := q.v[env|place(n, kept, a)]
+			if va == 0 {
+				continue
+			}
+			for b := 0; b < dim; b++ {
+				rho[a][b] += va * cmplx.Conj(q.v[env|place(n, kept, b)])
+			}
+		}
+	}
+	return rho
+}
+
+// PartialTrace : Returns the density matrix of the given bits with every other bit traced out,
+// bit[0] becomes the most significant bit of the result
+func PartialTrace(rho matrix.Matrix, bit ...int) matrix.Matrix {
+	n := int(math.Round(math.Log2(float64(len(rho)))))
+	kept, rest := split(n, bit)
+	dim := 1 << uint(len(bit))
+	reduced := make(matrix.Matrix, dim)
+	for a := range reduced {
+		reduced[a] = make([]complex128, dim)
+		for b := range reduced[a] {
+			for e := 0; e < 1<<uint(len(rest)); e++ {
+				env := place(n, rest, e)
+				reduced[a][b] += rho[env|place(n, kept, a)][env|place(n, kept, b)]
+			}
+		}
+	}
+	return reduced
+}
+
+// Bloch : Returns the Bloch vector (x, y, z) of a bit, the expectations of X, Y and Z on its
+// reduced state. The length of the vector is one for a pure bit and less when it is entangled
+func (q *Qubit) Bloch(bit int) (x, y, z float64) {
+	return BlochVector(q.ReducedDensityMatrix(bit))
+}
+
+// BlochVector : Returns the Bloch vector (x, y, z) of a single bit density matrix,
+// rho = (I + xX + yY + zZ) / 2
+func BlochVector(rho matrix.Matrix) (x, y, z float64) {
+	if len(rho) != 2 {
+		panic(fmt.Sprintf("qubit: expected a 2x2 density matrix, got %d rows", len(rho)))
+	}
+	return 2 * real(rho[1][0]), 2 * imag(rho[1][0]), real(rho[0][0] - rho[1][1])
+}
+
+// Purity : Returns Tr(rho^2) of the reduced state of the given bits, one when they are in a
+// pure state and 1/2^len(bit) when maximally mixed
+func (q *Qubit) Purity(bit ...int) float64 {
+	return Purity(q.ReducedDensityMatrix(bit...))
+}
+
+// Purity : Returns Tr(rho^2) of a density matrix
+func Purity(rho matrix.Matrix) (purity float64) {
+	// rho is hermitian, so Tr(rho^2) is the sum of |rho_ij|^2
+	for i := range rho {
+		for j := range rho[i] {
+			purity += math.Pow(cmplx.Abs(rho[i][j]), 2)
+		}
+	}
+	return
+}
+
+// split : Returns the bits to keep and the remaining bits of an n bit state, panics when a
+// bit is out of range or repeated
+func split(n int, bit []int) (kept, rest []int) {
+	seen := make([]bool, n)
+	for _, b := range bit {
+		if b < 0 || b >= n || seen[b] {
+			panic(fmt.Sprintf("qubit: bit %d is out of range or repeated in %d bits", b, n))
+		}
+		seen[b] = true
+	}
+	for b := 0; b < n; b++ {
+		if !seen[b] {
+			rest = append(rest, b)
+		}
+	}
+	return bit, rest
+}
+
+// place : Returns the index of an n bit state with the bits of value spread over the given
+// bits, the most significant bit of value on bit[0]
+func place(n int, bit []int, value int) (index int) {
+	for i, b := range bit {
+		if value>>uint(len(bit)-1-i)&1 == 1 {
+			index |= 1 << uint(n-1-b)
+		}
+	}
+	return
+}
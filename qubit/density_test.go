@@ -0,0 +1,106 @@
+package qubit
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/benluxford/qe/matrix"
+)
+
+func TestReducedDensityMatrix(t *testing.T) {
+	s := complex(1/math.Sqrt2, 0)
+	// |0>|+>
+	product := New(s, s, 0, 0)
+	bell := New(s, 0, 0, s)
+	tests := []struct {
+		name string
+		q    *Qubit
+		bit  []int
+		want matrix.Matrix
+	}{
+		{"product first", product, []int{0}, matrix.Matrix{{1, 0}, {0, 0}}},
+		{"product second", product, []int{1}, matrix.Matrix{{0.5, 0.5}, {0.5, 0.5}}},
+		{"bell", bell, []int{1}, matrix.Matrix{{0.5, 0}, {0, 0.5}}},
+		{"all bits", bell, []int{0, 1}, bell.DensityMatrix()},
+		// |01> with its bits swapped is |10>
+		{"reordered", New(0, 1, 0, 0), []int{1, 0}, matrix.Matrix{{0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 1, 0}, {0, 0, 0, 0}}},
+	}
+	for _, tt := range tests {
+		if got := tt.q.ReducedDensityMatrix(tt.bit...); !got.Equals(tt.want, 1e-12) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// the partial trace of the full density matrix is the reduced state, in any bit order
+	rng := rand.New(rand.NewSource(1))
+	amplitudes := make([]complex128, 8)
+	for i := range amplitudes {
+		amplitudes[i] = complex(rng.NormFloat64(), rng.NormFloat64())
+	}
+	q := New(amplitudes...)
+	for _, bit := range [][]int{{0}, {2}, {0, 2}, {2, 0}, {1, 2, 0}} {
+		want := q.ReducedDensityMatrix(bit...)
+		if got := PartialTrace(q.DensityMatrix(), bit...); !got.Equals(want, 1e-12) {
+			t.Errorf("bits %v: PartialTrace does not match ReducedDensityMatrix", bit)
+		}
+		trace := complex128(0)
+		for i := range want {
+			trace += want[i][i]
+		}
+		if math.Abs(real(trace)-1) > 1e-12 || math.Abs(imag(trace)) > 1e-12 {
+			t.Errorf("bits %v: trace %v", bit, trace)
+		}
+	}
+}
+
+func TestPurityAndBloch(t *testing.T) {
+	s := complex(1/math.Sqrt2, 0)
+	tests := []struct {
+		name    string
+		q       *Qubit
+		bit     int
+		purity  float64
+		x, y, z float64
+	}{
+		{"|0>", Zero(), 0, 1, 0, 0, 1},
+		{"|1>", One(), 0, 1, 0, 0, -1},
+		{"|+>", New(s, s), 0, 1, 1, 0, 0},
+		{"|-i>", New(s, -1i*s), 0, 1, 0, -1, 0},
+		{"product, |0> half", New(s, s, 0, 0), 0, 1, 0, 0, 1},
+		{"product, |+> half", New(s, s, 0, 0), 1, 1, 1, 0, 0},
+		{"bell", New(s, 0, 0, s), 0, 0.5, 0, 0, 0},
+		{"bell", New(s, 0, 0, s), 1, 0.5, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.q.Purity(tt.bit); math.Abs(got-tt.purity) > 1e-12 {
+			t.Errorf("%s bit %d: got purity %v, want %v", tt.name, tt.bit, got, tt.purity)
+		}
+		x, y, z := tt.q.Bloch(tt.bit)
+		if math.Abs(x-tt.x) > 1e-12 || math.Abs(y-tt.y) > 1e-12 || math.Abs(z-tt.z) > 1e-12 {
+			t.Errorf("%s bit %d: got Bloch vector (%v, %v, %v), want (%v, %v, %v)", tt.name, tt.bit, x, y, z, tt.x, tt.y, tt.z)
+		}
+	}
+	// an entangled pair is pure as a whole, a maximally mixed pair is a quarter
+	bell := New(s, 0, 0, s)
+	if got := bell.Purity(0, 1); math.Abs(got-1) > 1e-12 {
+		t.Errorf("bell pair: got purity %v, want 1", got)
+	}
+	mixed := matrix.Matrix{{0.25, 0, 0, 0}, {0, 0.25, 0, 0}, {0, 0, 0.25, 0}, {0, 0, 0, 0.25}}
+	if got := Purity(mixed); math.Abs(got-0.25) > 1e-12 {
+		t.Errorf("maximally mixed: got purity %v, want 0.25", got)
+	}
+}
+
+func TestReducedDensityMatrixPanics(t *testing.T) {
+	for _, bit := range [][]int{{0, 0}, {2}, {-1}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("bits %v: expected a panic", bit)
+				}
+			}()
+			New(1, 0, 0, 0).ReducedDensityMatrix(bit...)
+		}()
+	}
+}
@@ -0,0 +1,47 @@
+package qubit
+
+import (
+	"math"
+
+	"github.com/benluxford/qe/matrix"
+)
+
+// UhlmannFidelity : Returns the fidelity (Tr sqrt(sqrt(rho) sigma sqrt(rho)))^2 of two density
+// matrices, which reduces to |<psi|phi>|^2 for pure states
+func UhlmannFidelity(rho, sigma matrix.Matrix) float64 {
+	root := sqrtHermitian(rho)
+	values, _ := root.Apply(sigma).Apply(root).EigenHermitian()
+	sum := 0.0
+	for _, v := range values {
+		// rounding noise on zero eigenvalues would be magnified by the square root
+		if v > entanglementEps {
+			sum += math.Sqrt(v)
+		}
+	}
+	return sum * sum
+}
+
+// TraceNorm : Returns the sum of the singular values of a matrix, Tr sqrt(m^dagger m), which
+// is the sum of the magnitudes of the eigenvalues for a hermitian matrix
+func TraceNorm(m matrix.Matrix) (norm float64) {
+	if m.IsHermite(entanglementEps) {
+		values, _ := m.EigenHermitian()
+		for _, v := range values {
+			norm += math.Abs(v)
+		}
+		return
+	}
+	values, _ := m.Dagger().Apply(m).EigenHermitian()
+	for _, v := range values {
+		if v > entanglementEps {
+			norm += math.Sqrt(v)
+		}
+	}
+	return
+}
+
+// TraceDistance : Returns half the trace norm of the difference of two density matrices,
+// zero for equal states and one for orthogonal ones
+func TraceDistance(rho, sigma matrix.Matrix) float64 {
+	return TraceNorm(rho.Subtract(sigma)) / 2
+}
@@ -0,0 +1,92 @@
+package qubit
+
+import (
+	"math"
+	"testing"
+
+	"github.com/benluxford/qe/matrix"
+)
+
+func TestPureDistances(t *testing.T) {
+	s := complex(1/math.Sqrt2, 0)
+	tests := []struct {
+		name                                     string
+		a, b                                     *Qubit
+		fidelity, distance, classical, variation float64
+	}{
+		{"identical", New(s, s), New(s, s), 1, 0, 1, 0},
+		{"global phase", New(0, 1i), One(), 1, 0, 1, 0},
+		{"orthogonal", Zero(), One(), 0, 1, 0, 1},
+		{"plus and zero", New(s, s), Zero(), 0.5, math.Sqrt(0.5), math.Sqrt(0.5), 0.5},
+		// the measurement distributions cannot tell |+> and |-> apart
+		{"plus and minus", New(s, s), New(s, -s), 0, 1, 1, 0},
+		{"bell and zero", New(s, 0, 0, s), Zero(2), 0.5, math.Sqrt(0.5), math.Sqrt(0.5), 0.5},
+	}
+	for _, tt := range tests {
+		got := []float64{tt.a.Fidelity(tt.b), tt.a.TraceDistance(tt.b), tt.a.ClassicalFidelity(tt.b), tt.a.TotalVariationDistance(tt.b)}
+		want := []float64{tt.fidelity, tt.distance, tt.classical, tt.variation}
+		for i, name := range []string{"Fidelity", "TraceDistance", "ClassicalFidelity", "TotalVariationDistance"} {
+			if math.Abs(got[i]-want[i]) > 1e-9 {
+				t.Errorf("%s: %s is %v, want %v", tt.name, name, got[i], want[i])
+			}
+		}
+		// the density matrix forms agree with the pure state ones
+		rho, sigma := tt.a.DensityMatrix(), tt.b.DensityMatrix()
+		if f := UhlmannFidelity(rho, sigma); math.Abs(f-tt.fidelity) > 1e-6 {
+			t.Errorf("%s: UhlmannFidelity is %v, want %v", tt.name, f, tt.fidelity)
+		}
+		if d := TraceDistance(rho, sigma); math.Abs(d-tt.distance) > 1e-6 {
+			t.Errorf("%s: TraceDistance is %v, want %v", tt.name, d, tt.distance)
+		}
+	}
+}
+
+func TestMixedDistances(t *testing.T) {
+	mixed := matrix.Matrix{{0.5, 0}, {0, 0.5}}
+	zero := matrix.Matrix{{1, 0}, {0, 0}}
+	plus := matrix.Matrix{{0.5, 0.5}, {0.5, 0.5}}
+	tests := []struct {
+		name               string
+		rho, sigma         matrix.Matrix
+		fidelity, distance float64
+	}{
+		{"maximally mixed", mixed, mixed, 1, 0},
+		{"mixed and pure", mixed, zero, 0.5, 0.5},
+		{"mixed and plus", mixed, plus, 0.5, 0.5},
+		// (sqrt(0.9 * 0.1) + sqrt(0.1 * 0.9))^2
+		{"diagonal", matrix.Matrix{{0.9, 0}, {0, 0.1}}, matrix.Matrix{{0.1, 0}, {0, 0.9}}, 0.36, 0.8},
+		{"diagonal and pure", matrix.Matrix{{0.75, 0}, {0, 0.25}}, zero, 0.75, 0.25},
+	}
+	for _, tt := range tests {
+		if got := UhlmannFidelity(tt.rho, tt.sigma); math.Abs(got-tt.fidelity) > 1e-6 {
+			t.Errorf("%s: UhlmannFidelity is %v, want %v", tt.name, got, tt.fidelity)
+		}
+		if got := UhlmannFidelity(tt.sigma, tt.rho); math.Abs(got-tt.fidelity) > 1e-6 {
+			t.Errorf("%s: UhlmannFidelity is not symmetric, %v", tt.name, got)
+		}
+		if got := TraceDistance(tt.rho, tt.sigma); math.Abs(got-tt.distance) > 1e-9 {
+			t.Errorf("%s: TraceDistance is %v, want %v", tt.name, got, tt.distance)
+		}
+	}
+}
+
+func TestTraceNorm(t *testing.T) {
+	tests := []struct {
+		name string
+		m    matrix.Matrix
+		want float64
+	}{
+		{"zero", matrix.Matrix{{0, 0}, {0, 0}}, 0},
+		{"identity", matrix.Matrix{{1, 0}, {0, 1}}, 2},
+		{"negative eigenvalue", matrix.Matrix{{1, 0}, {0, -2}}, 3},
+		{"pauli y", matrix.Matrix{{0, -1i}, {1i, 0}}, 2},
+		{"nilpotent", matrix.Matrix{{0, 1}, {0, 0}}, 1},
+		// the singular values s1, s2 have s1^2 + s2^2 = 30 and s1 s2 = |det| = 2
+		{"not hermitian", matrix.Matrix{{1, 2}, {3, 4}}, math.Sqrt(34)},
+	}
+	for _, tt := range tests {
+		if got := TraceNorm(tt.m); math.Abs(got-tt.want) > 1e-6 {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package qubit
+
+import (
+	"math"
+	"math/cmplx"
+
+	"github.com/benluxford/qe/matrix"
+)
+
+// entanglementEps : Eigenvalues and Schmidt coefficients below it are treated as zero
+const entanglementEps = 1e-12
+
+// Schmidt : Returns the Schmidt decomposition of the Qubit across the bipartition of the given
+// bits and the remaining bits, q = sum_k coefficients[k] |left[k]>|right[k]>. Coefficients are
+// in decreasing order, left states hold the given bits in the given order and right states the
+// remaining bits in increasing order
+func (q *Qubit) Schmidt(bit ...int) (coefficients []float64, left, right []*Qubit) {
+	n := q.NumberOfBit()
+	kept, rest := split(n, bit)
+	rows, columns := 1<<uint(len(kept)), 1<<uint(len(rest))
+	// the amplitudes as a matrix from the left bits to the right bits
+	m := make(matrix.Matrix, rows)
+	for a := range m {
+		m[a] = make([]complex128, columns)
+		for b := range m[a] {
+			m[a][b] = q.v[place(n, kept, a)|place(n, rest, b)]
+		}
+	}
+	// the left states are the eigenvectors of the reduced density matrix m m^dagger
+	rho := make(matrix.Matrix, rows)
+	for a := range rho {
+		rho[a] = make([]complex128, rows)
+		for a2 := range rho[a] {
+			for b := range m[a] {
+				rho[a][a2] += m[a][b] * cmplx.Conj(m[a2][b])
+			}
+		}
+	}
+	values, vectors := rho.EigenHermitian()
+	for k, value := range values {
+		if value < entanglementEps {
+			break
+		}
+		s := math.Sqrt(value)
+		u := make([]complex128, rows)
+		for a := range u {
+			u[a] = vectors[a][k]
+		}
+		// the right state is m^T u* / s
+		w := make([]complex128, columns)
+		for b := range w {
+			for a := range u {
+				w[b] += cmplx.Conj(u[a]) * m[a][b]
+			}
+			w[b] /= complex(s, 0)
+		}
+		coefficients = append(coefficients, s)
+		left = append(left, &Qubit{u})
+		right = append(right, &Qubit{w})
+	}
+	return
+}
+
+// EntanglementEntropy : Returns the von Neumann entropy, in bits, of the reduced state of the
+// given bits, zero for a product state and one for each Bell pair cut
+func (q *Qubit) EntanglementEntropy(bit ...int) float64 {
+	return VonNeumannEntropy(q.ReducedDensityMatrix(bit...))
+}
+
+// RenyiEntropy : Returns the Renyi entropy of order alpha, in bits, of the reduced state of the
+// given bits
+func (q *Qubit) RenyiEntropy(alpha float64, bit ...int) float64 {
+	return RenyiEntropy(q.ReducedDensityMatrix(bit...), alpha)
+}
+
+// Concurrence : Returns the concurrence of the two given bits, from zero for a separable pair
+// to one for a Bell pair
+func (q *Qubit) Concurrence(a, b int) float64 {
+	return Concurrence(q.ReducedDensityMatrix(a, b))
+}
+
+// Negativity : Returns the negativity across the bipartition of the given bits and the rest
+func (q *Qubit) Negativity(bit ...int) float64 {
+	return Negativity(q.DensityMatrix(), bit...)
+}
+
+// VonNeumannEntropy : Returns -Tr(rho log2 rho) of a density matrix
+func VonNeumannEntropy(rho matrix.Matrix) (entropy float64) {
+	values, _ := rho.EigenHermitian()
+	for _, p := range values {
+		if p > entanglementEps {
+			entropy -= p * math.Log2(p)
+		}
+	}
+	return
+}
+
+// RenyiEntropy : Returns log2(Tr(rho^alpha)) / (1 - alpha) of a density matrix, the von Neumann
+// entropy when alpha is one and the log of the rank when alpha is zero
+func RenyiEntropy(rho matrix.Matrix, alpha float64) float64 {
+	if alpha == 1 {
+		return VonNeumannEntropy(rho)
+	}
+	values, _ := rho.EigenHermitian()
+	sum := 0.0
+	for _, p := range values {
+		if p > entanglementEps {
+			sum += math.Pow(p, alpha)
+		}
+	}
+	return math.Log2(sum) / (1 - alpha)
+}
+
+// Concurrence : Returns the Wootters concurrence of a two bit density matrix,
+// max(0, l1 - l2 - l3 - l4) over the decreasing square roots of the eigenvalues of
+// sqrt(rho) Y⊗Y rho* Y⊗Y sqrt(rho)
+func Concurrence(rho matrix.Matrix) float64 {
+	yy := matrix.Matrix{
+		{0, 0, 0, -1},
+		{0, 0, 1, 0},
+		{0, 1, 0, 0},
+		{-1, 0, 0, 0},
+	}
+	flipped := yy.Apply(rho.Conjugate()).Apply(yy)
+	root := sqrtHermitian(rho)
+	values, _ := root.Apply(flipped).Apply(root).EigenHermitian()
+	l := make([]float64, len(values))
+	for i, v := range values {
+		// rounding leaves ~1e-17 where zero is meant, whose square root would be ~1e-9
+		if v > entanglementEps {
+			l[i] = math.Sqrt(v)
+		}
+	}
+	return math.Max(0, l[0]-l[1]-l[2]-l[3])
+}
+
+// Negativity : Returns the negativity of a density matrix across the bipartition of the given
+// bits and the rest, the sum of the magnitudes of the negative eigenvalues of the partial
+// transpose, (||rho^T_A||_1 - 1) / 2
+func Negativity(rho matrix.Matrix, bit ...int) (negativity float64) {
+	values, _ := PartialTranspose(rho, bit...).EigenHermitian()
+	for _, v := range values {
+		if v < 0 {
+			negativity -= v
+		}
+	}
+	return
+}
+
+// PartialTranspose : Returns the density matrix transposed on the given bits only
+func PartialTranspose(rho matrix.Matrix, bit ...int) matrix.Matrix {
+	n := bits(len(rho))
+	split(n, bit)
+	mask := place(n, bit, 1<<uint(len(bit))-1)
+	transposed := make(matrix.Matrix, len(rho))
+	for i := range transposed {
+		transposed[i] = make([]complex128, len(rho))
+	}
+	// the given bits of the row and column indices swap places
+	for i := range rho {
+		for j := range rho[i] {
+			ti, tj := i&^mask|j&mask, j&^mask|i&mask
+			transposed[ti][tj] = rho[i][j]
+		}
+	}
+	return transposed
+}
+
+// sqrtHermitian : Returns the square root of a positive semidefinite hermitian matrix
+func sqrtHermitian(input matrix.Matrix) matrix.Matrix {
+	values, vectors := input.EigenHermitian()
+	root := make(matrix.Matrix, len(input))
+	for i := range root {
+		root[i] = make([]complex128, len(input))
+		for j := range root[i] {
+			for k, v := range values {
+				root[i][j] += complex(math.Sqrt(math.Max(v, 0)), 0) * vectors[i][k] * cmplx.Conj(vectors[j][k])
+			}
+		}
+	}
+	return root
+}
@@ -0,0 +1,110 @@
+package qubit
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+// near : Fails unless got is within 1e-9 of want
+func near(t *testing.T, name string, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("%s = %g, want %g", name, got, want)
+	}
+}
+
+func TestEntanglementOfBellStates(t *testing.T) {
+	bell := map[string]*Qubit{
+		"Phi+":  New(1, 0, 0, 1),
+		"Phi-":  New(1, 0, 0, -1),
+		"Psi+":  New(0, 1, 1, 0),
+		"Psi-":  New(0, 1, -1, 0),
+		"i Phi": New(1, 0, 0, 1i),
+	}
+	for name, q := range bell {
+		near(t, name+" entropy", q.EntanglementEntropy(0), 1)
+		near(t, name+" entropy of bit 1", q.EntanglementEntropy(1), 1)
+		near(t, name+" Renyi 2 entropy", q.RenyiEntropy(2, 0), 1)
+		near(t, name+" concurrence", q.Concurrence(0, 1), 1)
+		near(t, name+" negativity", q.Negativity(0), 0.5)
+		coefficients, _, _ := q.Schmidt(0)
+		if len(coefficients) != 2 {
+			t.Fatalf("%s: Schmidt coefficients %v", name, coefficients)
+		}
+		near(t, name+" Schmidt coefficient", coefficients[0], 1/math.Sqrt2)
+		near(t, name+" Schmidt coefficient", coefficients[1], 1/math.Sqrt2)
+	}
+}
+
+func TestEntanglementOfGHZStates(t *testing.T) {
+	for n := 3; n <= 5; n++ {
+		amplitudes := make([]complex128, 1<<uint(n))
+		amplitudes[0], amplitudes[len(amplitudes)-1] = 1, 1
+		ghz := New(amplitudes...)
+		// every cut of a GHZ state leaves one bit of entropy
+		cuts := [][]int{{0}, {n - 1}, {0, 1}, {1, n - 1}}
+		for _, cut := range cuts {
+			near(t, "GHZ entropy", ghz.EntanglementEntropy(cut...), 1)
+			near(t, "GHZ Renyi 2 entropy", ghz.RenyiEntropy(2, cut...), 1)
+			near(t, "GHZ negativity", ghz.Negativity(cut...), 0.5)
+		}
+		// while any pair on its own is a classical mixture
+		near(t, "GHZ pair concurrence", ghz.Concurrence(0, 1), 0)
+	}
+}
+
+func TestEntanglementOfProductStates(t *testing.T) {
+	products := []*Qubit{
+		Zero(3),
+		TensorProduct(New(1, 1), New(1, 1i), New(3, 4)),
+		TensorProduct(New(1, 0, 0, 1), New(0.6, 0.8)),
+	}
+	for i, q := range products {
+		cut := []int{q.NumberOfBit() - 1}
+		near(t, "product entropy", q.EntanglementEntropy(cut...), 0)
+		near(t, "product Renyi 2 entropy", q.RenyiEntropy(2, cut...), 0)
+		near(t, "product negativity", q.Negativity(cut...), 0)
+		if coefficients, _, _ := q.Schmidt(cut...); len(coefficients) != 1 {
+			t.Errorf("product %d: Schmidt coefficients %v", i, coefficients)
+		}
+		if i < 2 {
+			near(t, "product concurrence", q.Concurrence(0, 1), 0)
+		}
+	}
+}
+
+func TestEntanglementOfPartiallyEntangledStates(t *testing.T) {
+	for _, theta := range []float64{0.1, 0.4, math.Pi / 6} {
+		c, s := math.Cos(theta), math.Sin(theta)
+		q := New(complex(c, 0), 0, 0, complex(s, 0))
+		p := c * c
+		near(t, "entropy", q.EntanglementEntropy(0), -p*math.Log2(p)-(1-p)*math.Log2(1-p))
+		near(t, "Renyi 2 entropy", q.RenyiEntropy(2, 0), -math.Log2(p*p+(1-p)*(1-p)))
+		near(t, "concurrence", q.Concurrence(0, 1), math.Sin(2*theta))
+		near(t, "negativity", q.Negativity(1), math.Sin(2*theta)/2)
+	}
+	// the W state leaves each pair with concurrence 2/3
+	w := New(0, 1, 1, 0, 1, 0, 0, 0)
+	near(t, "W concurrence", w.Concurrence(0, 2), 2.0/3)
+}
+
+func TestSchmidtRebuildsTheState(t *testing.T) {
+	q := New(0.1, 0.3i, -0.2, 0.5, 0.4, 0, 0.2-0.1i, 0.6)
+	coefficients, left, right := q.Schmidt(2, 0)
+	// left holds bits 2 and 0 in that order and right holds bit 1
+	rebuilt := make([]complex128, 8)
+	for k, s := range coefficients {
+		for a, l := range left[k].Amplitude() {
+			for b, r := range right[k].Amplitude() {
+				index := (a&1)<<2 | (a>>1)&1 | b<<1
+				rebuilt[index] += complex(s, 0) * l * r
+			}
+		}
+	}
+	for i, a := range q.Amplitude() {
+		if cmplx.Abs(rebuilt[i]-a) > 1e-9 {
+			t.Fatalf("rebuilt %v, want %v", rebuilt, q.Amplitude())
+		}
+	}
+}
@@ -0,0 +1,117 @@
+// Package qubit holds quantum states and the operations on them.
+//
+// Bit ordering: across qe, bit 0 is the most significant bit of an amplitude's index, so
+// amplitude i of an n bit state is the basis state whose bits read i in binary from bit 0 to
+// bit n-1. The leftmost factor of a tensor product (qubit, vector or matrix) is bit 0, gate
+// constructors such as gate.Controlled and gate.CNOT number their bits the same way, and kets
+// are written |b0 b1 ... bn-1>. This is the BigEndian Order, other toolkits often number bits
+// from the least significant end, the LittleEndian Order converts to and from that view.
+package qubit
+
+import (
+	"fmt"
+
+	"github.com/benluxford/qe/matrix"
+)
+
+// Order : The order bits are numbered in an index, or written in a ket
+type Order int
+
+const (
+	// BigEndian : Bit 0 is the most significant bit and written first, |b0 b1 ... bn-1>
+	BigEndian Order = iota
+	// LittleEndian : Bit 0 is the least significant bit and written last, |bn-1 ... b1 b0>
+	LittleEndian
+)
+
+// String : Returns the name of the Order
+func (o Order) String() string {
+	if o == LittleEndian {
+		return "little endian"
+	}
+	return "big endian"
+}
+
+// Bit : Returns the value (0 or 1) of a bit in the index of an n bit basis state
+func (o Order) Bit(index, bit, n int) int {
+	if o == LittleEndian {
+		return index >> uint(bit) & 1
+	}
+	return index >> uint(n-1-bit) & 1
+}
+
+// Index : Returns the index of an n bit basis state in the library's BigEndian order from
+// its index in this Order
+func (o Order) Index(index, n int) int {
+	if o == BigEndian {
+		return index
+	}
+	return reverse(index, n)
+}
+
+// Amplitudes : Returns the amplitudes reordered between BigEndian and this Order, the
+// conversion is its own inverse so it both imports and exports
+func (o Order) Amplitudes(input []complex128) []complex128 {
+	n := bits(len(input))
+	output := make([]complex128, len(input))
+	for i, a := range input {
+		output[o.Index(i, n)] = a
+	}
+	return output
+}
+
+// Matrix : Returns the matrix with rows and columns reordered between BigEndian and this Order,
+// e.g. to import or export a unitary from a toolkit that numbers bits the other way
+func (o Order) Matrix(input matrix.Matrix) matrix.Matrix {
+	n := bits(len(input))
+	output := make(matrix.Matrix, len(input))
+	for i := range output {
+		output[i] = make([]complex128, len(input))
+	}
+	for i, row := range input {
+		for j, value := range row {
+			output[o.Index(i, n)][o.Index(j, n)] = value
+		}
+	}
+	return output
+}
+
+// FromAmplitudes : Returns a new normalised Qubit from amplitudes indexed in the given Order
+func FromAmplitudes(order Order, input ...complex128) *Qubit {
+	return New(order.Amplitudes(input)...)
+}
+
+// AmplitudeIn : Returns the amplitudes of the Qubit indexed in the given Order
+func (q *Qubit) AmplitudeIn(order Order) []complex128 {
+	return order.Amplitudes(q.Amplitude())
+}
+
+// ProbabilityIn : Returns the probabilities of the Qubit indexed in the given Order
+func (q *Qubit) ProbabilityIn(order Order) (probability []float64) {
+	p := q.Probability()
+	probability = make([]float64, len(p))
+	n := q.NumberOfBit()
+	for i := range p {
+		probability[order.Index(i, n)] = p[i]
+	}
+	return
+}
+
+// reverse : Returns the index with its n bits in reverse order
+func reverse(index, n int) (reversed int) {
+	for i := 0; i < n; i++ {
+		reversed = reversed<<1 | index>>uint(i)&1
+	}
+	return
+}
+
+// bits : Returns the number of bits of a dimension, panics unless it is a power of two
+func bits(dim int) (n int) {
+	for 1<<uint(n) < dim {
+		n++
+	}
+	if 1<<uint(n) != dim {
+		panic(fmt.Sprintf("qubit: dimension %d is not a power of two", dim))
+	}
+	return
+}
@@ -0,0 +1,98 @@
+package qubit
+
+import (
+	"math"
+	"testing"
+
+	"github.com/benluxford/qe/matrix"
+)
+
+// |001⟩ and |100⟩ swap places between the orders, 0.6|001⟩ + 0.8|100⟩ read big endian is
+// 0.8|001⟩ + 0.6|100⟩ read little endian
+var (
+	bigEndian    = []complex128{0, 0.6, 0, 0, 0.8, 0, 0, 0}
+	littleEndian = []complex128{0, 0.8, 0, 0, 0.6, 0, 0, 0}
+)
+
+func TestOrderBitAndIndex(t *testing.T) {
+	tests := []struct {
+		order      Order
+		index, bit int
+		want       int
+		wantIndex  int
+	}{
+		// index 1 is |001⟩, bit 2 set, in big endian and bit 0 set in little endian
+		{BigEndian, 1, 2, 1, 1},
+		{BigEndian, 1, 0, 0, 1},
+		{LittleEndian, 1, 0, 1, 4},
+		{LittleEndian, 1, 2, 0, 4},
+		// index 6 is 110, bits 0 and 1 in big endian, bits 1 and 2 in little endian
+		{BigEndian, 6, 0, 1, 6},
+		{LittleEndian, 6, 0, 0, 3},
+		{LittleEndian, 6, 2, 1, 3},
+		// palindromes are the same in both
+		{LittleEndian, 5, 1, 0, 5},
+	}
+	for _, tt := range tests {
+		if got := tt.order.Bit(tt.index, tt.bit, 3); got != tt.want {
+			t.Errorf("%v: bit %d of %d is %d, want %d", tt.order, tt.bit, tt.index, got, tt.want)
+		}
+		if got := tt.order.Index(tt.index, 3); got != tt.wantIndex {
+			t.Errorf("%v: index %d is %d big endian, want %d", tt.order, tt.index, got, tt.wantIndex)
+		}
+	}
+}
+
+func TestOrderAmplitudes(t *testing.T) {
+	// the state is read in from little endian amplitudes and written back out
+	q := FromAmplitudes(LittleEndian, littleEndian...)
+	if got := q.Amplitude(); !equal(got, bigEndian) {
+		t.Errorf("imported %v, want %v", got, bigEndian)
+	}
+	if got := q.AmplitudeIn(LittleEndian); !equal(got, littleEndian) {
+		t.Errorf("exported %v, want %v", got, littleEndian)
+	}
+	if got := q.AmplitudeIn(BigEndian); !equal(got, bigEndian) {
+		t.Errorf("big endian %v, want %v", got, bigEndian)
+	}
+	want := []float64{0, 0.64, 0, 0, 0.36, 0, 0, 0}
+	for i, p := range q.ProbabilityIn(LittleEndian) {
+		if math.Abs(p-want[i]) > 1e-12 {
+			t.Errorf("got probabilities %v, want %v", q.ProbabilityIn(LittleEndian), want)
+			break
+		}
+	}
+	// bit 0 is set in the little endian |001⟩, which is |100⟩ big endian
+	if got := FromAmplitudes(LittleEndian, 0, 1, 0, 0, 0, 0, 0, 0).Amplitude(); got[4] != 1 {
+		t.Errorf("little endian |001⟩ is %v", got)
+	}
+}
+
+func TestOrderMatrix(t *testing.T) {
+	// a CNOT from bit 0 to bit 1 flips bit 1 of |10⟩ (index 2) big endian, and of |01⟩
+	// (index 1) little endian
+	cnot := matrix.Matrix{{1, 0, 0, 0}, {0, 1, 0, 0}, {0, 0, 0, 1}, {0, 0, 1, 0}}
+	want := matrix.Matrix{{1, 0, 0, 0}, {0, 0, 0, 1}, {0, 0, 1, 0}, {0, 1, 0, 0}}
+	if got := LittleEndian.Matrix(cnot); !got.Equals(want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got := BigEndian.Matrix(cnot); !got.Equals(cnot) {
+		t.Errorf("big endian changed the matrix to %v", got)
+	}
+	if got := LittleEndian.Matrix(want); !got.Equals(cnot) {
+		t.Errorf("the conversion is not its own inverse, got %v", got)
+	}
+}
+
+// equal : Returns true if the amplitudes match
+func equal(a, b []complex128) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if math.Abs(real(a[i]-b[i])) > 1e-12 || math.Abs(imag(a[i]-b[i])) > 1e-12 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,114 @@
+package qubit
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"strconv"
+	"strings"
+)
+
+// Printer : Options for writing a Qubit in Dirac notation, e.g. 0.707|00⟩ + 0.707|11⟩
+type Printer struct {
+	// Threshold : Amplitudes with a magnitude below it are left out, as are those that round
+	// to zero at the Precision
+	Threshold float64
+	// Precision : The number of decimal places, trailing zeros are dropped
+	Precision int
+	// Polar : Write amplitudes as r e^(i theta) rather than a + bi
+	Polar bool
+	// Order : The order of the bits in each ket, see Order
+	Order Order
+	// Probabilities : Write the probability of each basis state after its ket
+	Probabilities bool
+}
+
+// DefaultPrinter : The Printer used by String, three decimal places in rectangular form
+var DefaultPrinter = Printer{Threshold: 1e-9, Precision: 3}
+
+// String : Returns the Qubit in Dirac notation using the DefaultPrinter
+func (q *Qubit) String() string {
+	return DefaultPrinter.Sprint(q)
+}
+
+// String : Returns the State in Dirac notation using the DefaultPrinter
+func (s *State[T]) String() string {
+	return DefaultPrinter.Sprint(s.Qubit())
+}
+
+// Sprint : Returns the Qubit in Dirac notation, or 0 when every amplitude is below the threshold
+// or rounds to zero
+func (p Printer) Sprint(q *Qubit) string {
+	n := q.NumberOfBit()
+	var b strings.Builder
+	for i, a := range q.v {
+		if cmplx.Abs(a) < p.Threshold || a == 0 {
+			continue
+		}
+		text := p.amplitude(a)
+		if text == "0" {
+			continue
+		}
+		// a leading minus becomes the sign joining the terms
+		switch {
+		case b.Len() == 0:
+		case strings.HasPrefix(text, "-"):
+			b.WriteString(" - ")
+			text = text[1:]
+		default:
+			b.WriteString(" + ")
+		}
+		b.WriteString(text + "|" + p.ket(i, n) + "⟩")
+		if p.Probabilities {
+			b.WriteString(" (p=" + p.number(math.Pow(cmplx.Abs(a), 2)) + ")")
+		}
+	}
+	if b.Len() == 0 {
+		return "0"
+	}
+	return b.String()
+}
+
+// ket : Returns the bits of a basis state in the order of the Printer
+func (p Printer) ket(index, n int) string {
+	bits := []byte(fmt.Sprintf("%0*b", n, index))
+	if p.Order == LittleEndian {
+		for i, j := 0, len(bits)-1; i < j; i, j = i+1, j-1 {
+			bits[i], bits[j] = bits[j], bits[i]
+		}
+	}
+	return string(bits)
+}
+
+// amplitude : Returns an amplitude in the form of the Printer, dropping parts that round to zero
+func (p Printer) amplitude(a complex128) string {
+	if p.Polar {
+		r, theta := cmplx.Polar(a)
+		if p.number(r) == "0" || p.number(theta) == "0" {
+			return p.number(r)
+		}
+		return p.number(r) + "e^(i" + p.number(theta) + ")"
+	}
+	re, im := p.number(real(a)), p.number(imag(a))
+	switch {
+	case im == "0":
+		return re
+	case re == "0":
+		return im + "i"
+	case strings.HasPrefix(im, "-"):
+		return "(" + re + im + "i)"
+	}
+	return "(" + re + "+" + im + "i)"
+}
+
+// number : Returns the value to the precision of the Printer without trailing zeros
+func (p Printer) number(value float64) string {
+	text := strconv.FormatFloat(value, 'f', p.Precision, 64)
+	if strings.Contains(text, ".") {
+		text = strings.TrimRight(strings.TrimRight(text, "0"), ".")
+	}
+	if text == "-0" {
+		return "0"
+	}
+	return text
+}
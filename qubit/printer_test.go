@@ -0,0 +1,45 @@
+package qubit
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPrinter(t *testing.T) {
+	s := complex(1/math.Sqrt2, 0)
+	bell := New(s, 0, 0, s)
+	tests := []struct {
+		name string
+		p    Printer
+		q    *Qubit
+		want string
+	}{
+		{"default", DefaultPrinter, bell, "0.707|00⟩ + 0.707|11⟩"},
+		{"zero", DefaultPrinter, Zero(2), "1|00⟩"},
+		{"minus", DefaultPrinter, New(-s, -s), "-0.707|0⟩ - 0.707|1⟩"},
+		{"complex", DefaultPrinter, New(0.5+0.5i, 0.5-0.5i), "(0.5+0.5i)|0⟩ + (0.5-0.5i)|1⟩"},
+		{"imaginary", DefaultPrinter, New(s, -1i*s), "0.707|0⟩ - 0.707i|1⟩"},
+		{"precision", Printer{Threshold: 1e-9, Precision: 5}, bell, "0.70711|00⟩ + 0.70711|11⟩"},
+		{"no decimals", Printer{Threshold: 1e-9}, New(0.6, 0.8), "1|0⟩ + 1|1⟩"},
+		// 0.1 of the state is left out by the threshold, the rest is not renormalised
+		{"threshold", Printer{Threshold: 0.2, Precision: 3}, New(0.1, 0, 0, complex(math.Sqrt(0.99), 0)), "0.995|11⟩"},
+		// above the threshold but zero to three places
+		{"rounds to zero", DefaultPrinter, New(1e-4, 1), "1|1⟩"},
+		{"everything rounds to zero", Printer{Threshold: 1e-9, Precision: 0}, New(1, 1, 1, 1, 1, 1, 1, 1), "0"},
+		{"polar", Printer{Threshold: 1e-9, Precision: 3, Polar: true}, New(s, 1i*s), "0.707|0⟩ + 0.707e^(i1.571)|1⟩"},
+		{"polar minus", Printer{Threshold: 1e-9, Precision: 2, Polar: true}, New(complex(-1/math.Sqrt2, 0), s), "0.71e^(i3.14)|0⟩ + 0.71|1⟩"},
+		{"polar rounds to zero", Printer{Threshold: 1e-9, Precision: 3, Polar: true}, New(-1e-4, 1), "1|1⟩"},
+		// |011⟩ big endian is written 110 with bit 0 last
+		{"little endian", Printer{Threshold: 1e-9, Precision: 3, Order: LittleEndian}, New(0, 0, 0, 1, 0, 0, 0, 0), "1|110⟩"},
+		{"big endian", DefaultPrinter, New(0, 0, 0, 1, 0, 0, 0, 0), "1|011⟩"},
+		{"probabilities", Printer{Threshold: 1e-9, Precision: 2, Probabilities: true}, New(0.6, 0.8i), "0.6|0⟩ (p=0.36) + 0.8i|1⟩ (p=0.64)"},
+	}
+	for _, tt := range tests {
+		if got := tt.p.Sprint(tt.q); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+	if got := bell.String(); got != DefaultPrinter.Sprint(bell) {
+		t.Errorf("String: got %q", got)
+	}
+}
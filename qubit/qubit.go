@@ -64,8 +64,20 @@ func (q *Qubit) Clone() *Qubit {
 	return &Qubit{q.v.Clone()}
 }
 
-// Fidelity : Returns the product of two Qubits probabilities
-func (q *Qubit) Fidelity(input *Qubit) (sum float64) {
+// Fidelity : Returns the quantum fidelity |<q|input>|^2 of two pure states, one for the same
+// state up to a global phase and zero for orthogonal states
+func (q *Qubit) Fidelity(input *Qubit) float64 {
+	return math.Pow(cmplx.Abs(input.v.InnerProduct(q.v)), 2)
+}
+
+// TraceDistance : Returns the trace distance of two pure states, sqrt(1 - Fidelity)
+func (q *Qubit) TraceDistance(input *Qubit) float64 {
+	return math.Sqrt(math.Max(0, 1-q.Fidelity(input)))
+}
+
+// ClassicalFidelity : Returns the Bhattacharyya coefficient of the two Qubits' measurement
+// distributions, sum sqrt(p_i q_i), which ignores phases so |+> and |-> score one
+func (q *Qubit) ClassicalFidelity(input *Qubit) (sum float64) {
 	// get the probability of the input Qubit
 	inputProbability := input.Probability()
 	// get the probability of the current qubit
@@ -77,8 +89,9 @@ func (q *Qubit) Fidelity(input *Qubit) (sum float64) {
 	return
 }
 
-// TraceDistance : Returns the sum of the distance between probability in current and input Qubit's vectors
-func (q *Qubit) TraceDistance(input *Qubit) (sum float64) {
+// TotalVariationDistance : Returns the total variation distance of the two Qubits' measurement
+// distributions, half the sum of the distance between their probabilities
+func (q *Qubit) TotalVariationDistance(input *Qubit) (sum float64) {
 	// get the probability of the input Qubit
 	inputProbability := input.Probability()
 	// get the probability of the current qubit
@@ -97,6 +110,11 @@ func (q *Qubit) Equals(input *Qubit, eps ...float64) bool {
 	return q.v.Equals(input.v, eps...)
 }
 
+// EqualsUpToPhase : Returns true if the given Qubits match once a global phase is removed
+func (q *Qubit) EqualsUpToPhase(input *Qubit, eps ...float64) bool {
+	return q.v.EqualsUpToPhase(input.v, eps...)
+}
+
 // TensorProduct : Returns the current Qubit with the tensor product of the input Qubit applied
 func (q *Qubit) TensorProduct(input *Qubit) *Qubit {
 	q.v = q.v.TensorProduct(input.v)
@@ -170,56 +188,28 @@ func (q *Qubit) Measure(bit ...int) *Qubit {
 	return q
 }
 
-// ProbabilityZeroAt : Returns the probability of zero at indices
+// ProbabilityZeroAt : Returns the indices with the given bit at zero and their probability,
+// bit 0 is the most significant bit of the index
 func (q *Qubit) ProbabilityZeroAt(bit int) (index []int, probability []float64) {
-	dim := q.v.Dimension()
-	den := int(math.Pow(2, float64(bit+1)))
-	div := dim / den
-
-	for i := 0; i < dim; i++ {
-		probability = append(probability, q.Probability()[i])
-		index = append(index, i)
-
-		if len(probability) == dim/2 {
-			break
-		}
-
-		if (i+1)%div == 0 {
-			i = i + div
-		}
-	}
-	return
+	return q.probabilityAt(bit, 0)
 }
 
-// ProbabilityOneAt : Returns the probability of one at indices
+// ProbabilityOneAt : Returns the indices with the given bit at one and their probability,
+// bit 0 is the most significant bit of the index
 func (q *Qubit) ProbabilityOneAt(bit int) (index []int, probability []float64) {
-	// get all the indices with probability of 0
-	zeroIndices, _ := q.ProbabilityZeroAt(bit)
-	// create slice to hold all probability of one indices
-	one := []int{}
-	// for each value in the Qubit's vector
+	return q.probabilityAt(bit, 1)
+}
+
+// probabilityAt : Returns the indices with the given bit at value and their probability
+func (q *Qubit) probabilityAt(bit, value int) (index []int, probability []float64) {
+	n := q.NumberOfBit()
+	all := q.Probability()
 	for i := range q.v {
-		found := false
-		// loop over all zero indices
-		for _, zeroIndex := range zeroIndices {
-			// if the index matches a zero index, found and break loop
-			if i == zeroIndex {
-				found = true
-				break
-			}
-		}
-		// if the index was not found in the zero index append to the one slice
-		if !found {
-			one = append(one, i)
+		if BigEndian.Bit(i, bit, n) == value {
+			index = append(index, i)
+			probability = append(probability, all[i])
 		}
 	}
-	// for each value in the one slice
-	for _, i := range one {
-		// get and append the probability of the vector component
-		probability = append(probability, q.Probability()[i])
-		// append the index of the given probability
-		index = append(index, i)
-	}
 	return
 }
 
@@ -246,24 +236,8 @@ func (q *Qubit) MeasureAt(bit int) *Qubit {
 		// return a Qubit in the one state
 		return One()
 	}
-	// create slice to hold all probability of one indices
-	one := []int{}
-	// for each value in the Qubit's vector
-	for i := range q.v {
-		found := false
-		// loop over all zero indices
-		for _, zeroIndex := range zeroIndices {
-			// if the index matches a zero index, found and break loop
-			if i == zeroIndex {
-				found = true
-				break
-			}
-		}
-		// if the index was not found in the zero index append to the one slice
-		if !found {
-			one = append(one, i)
-		}
-	}
+	// get the indices with one at the bit
+	one, _ := q.ProbabilityOneAt(bit)
 	// Set all the vector values to 0
 	for _, i := range one {
 		q.v[i] = complex(0, 0)
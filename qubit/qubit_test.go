@@ -0,0 +1,36 @@
+package qubit
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"github.com/benluxford/qe/matrix"
+)
+
+func TestEqualsUpToPhase(t *testing.T) {
+	h := matrix.Matrix{{1 / math.Sqrt2, 1 / math.Sqrt2}, {1 / math.Sqrt2, -1 / math.Sqrt2}}
+	z := matrix.Matrix{{1, 0}, {0, -1}}
+	phase := matrix.Matrix{{cmplx.Exp(0.4i), 0}, {0, cmplx.Exp(0.4i)}}
+	tests := []struct {
+		name     string
+		q, input *Qubit
+		want     bool
+	}{
+		{"identical", Zero().Apply(h), Zero().Apply(h), true},
+		// X = HZH and -X = ZX, so Z X |0> = -|1>
+		{"minus one", One(), Zero().Apply(h).Apply(z).Apply(h).Apply(z), true},
+		{"global phase", Zero(2), Zero(2).Apply(phase.TensorProduct(phase)), true},
+		{"relative phase", Zero().Apply(h), One().Apply(h), false},
+		{"orthogonal", Zero(), One(), false},
+		{"different size", Zero(), Zero(2), false},
+	}
+	for _, tt := range tests {
+		if got := tt.q.EqualsUpToPhase(tt.input, 1e-12); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+		if tt.want && tt.name != "identical" && tt.q.Equals(tt.input, 1e-12) {
+			t.Errorf("%s: Equals ignores the phase", tt.name)
+		}
+	}
+}
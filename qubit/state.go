@@ -0,0 +1,211 @@
+package qubit
+
+import (
+	"math"
+
+	"github.com/benluxford/qe/matrix"
+	v "github.com/benluxford/qe/vector"
+)
+
+// Complex : The amplitude precisions a State can be simulated in
+type Complex interface {
+	~complex64 | ~complex128
+}
+
+// State : A state vector generic over its amplitude precision. State[complex64] halves
+// the memory of a Qubit (a 30 bit register is 8 GiB rather than 16 GiB) at the cost of
+// precision: amplitudes carry roughly 7 significant digits instead of 16, and rounding
+// error grows with every sweep over the state. In practice this is around 1e-7 per
+// amplitude per gate, so a 10 bit QFT or a few dozen Grover iterations stay well within
+// 1e-4 of the complex128 result, comfortably enough to read off probabilities
+type State[T Complex] struct {
+	v []T
+}
+
+// NewState : Takes amplitudes as input, returns pointer to a new normalised State
+func NewState[T Complex](input ...T) (state *State[T]) {
+	// copy the amplitudes so the caller's slice is not modified
+	amplitudes := make([]T, len(input))
+	copy(amplitudes, input)
+	state = &State[T]{amplitudes}
+	// normalise the amplitudes
+	state.Normalise()
+	return
+}
+
+// ZeroState : Returns a new State of the given number of bits in zero state
+func ZeroState[T Complex](bit int) (state *State[T]) {
+	state = &State[T]{make([]T, 1<<uint(bit))}
+	state.v[0] = 1
+	return
+}
+
+// FromQubit : Returns a new State holding the amplitudes of the given Qubit
+func FromQubit[T Complex](q *Qubit) (state *State[T]) {
+	state = &State[T]{make([]T, len(q.v))}
+	for i, component := range q.v {
+		state.v[i] = T(component)
+	}
+	return
+}
+
+// Qubit : Returns the State converted to a complex128 Qubit
+func (s *State[T]) Qubit() *Qubit {
+	vector := v.NewZero(len(s.v))
+	for i, component := range s.v {
+		vector[i] = complex128(component)
+	}
+	return &Qubit{vector}
+}
+
+// NumberOfBit : Returns the number of bits in the State
+func (s *State[T]) NumberOfBit() int {
+	return int(math.Log2(float64(len(s.v))))
+}
+
+// Clone : Returns a clone of the current State
+func (s *State[T]) Clone() *State[T] {
+	amplitudes := make([]T, len(s.v))
+	copy(amplitudes, s.v)
+	return &State[T]{amplitudes}
+}
+
+// Amplitude : Returns a copy of the State's amplitudes
+func (s *State[T]) Amplitude() (a []T) {
+	a = make([]T, len(s.v))
+	copy(a, s.v)
+	return
+}
+
+// Probability : Returns the probability of each basis state, always in float64
+func (s *State[T]) Probability() (probabilityList []float64) {
+	probabilityList = make([]float64, len(s.v))
+	for i, component := range s.v {
+		c := complex128(component)
+		probabilityList[i] = real(c)*real(c) + imag(c)*imag(c)
+	}
+	return
+}
+
+// Normalise : Returns the current State with its amplitudes normalised
+func (s *State[T]) Normalise() *State[T] {
+	// accumulate in float64 so large complex64 states do not lose the sum
+	var sum float64
+	for _, p := range s.Probability() {
+		sum += p
+	}
+	z := T(complex(1/math.Sqrt(sum), 0))
+	for i := range s.v {
+		s.v[i] *= z
+	}
+	return s
+}
+
+// InnerProduct : Returns the product of the State with the conjugate of the input
+func (s *State[T]) InnerProduct(input *State[T]) (product complex128) {
+	for i := range s.v {
+		a, b := complex128(s.v[i]), complex128(input.v[i])
+		product += a * complex(real(b), -imag(b))
+	}
+	return
+}
+
+// Apply : Returns the current State with the full Matrix applied
+func (s *State[T]) Apply(input matrix.Matrix) *State[T] {
+	applied := make([]T, len(s.v))
+	for i := range input {
+		var component T
+		for j, value := range input[i] {
+			component += T(value) * s.v[j]
+		}
+		applied[i] = component
+	}
+	s.v = applied
+	return s
+}
+
+// ApplyAt : Returns the current State with a gate on len(bit) bits applied to the given
+// bits, in a single in-place sweep. bit[0] is the most significant bit of the gate, as
+// with the bit strings used throughout gate, and bit 0 is the most significant bit of the State
+func (s *State[T]) ApplyAt(input matrix.Matrix, bit ...int) *State[T] {
+	n := s.NumberOfBit()
+	dim := 1 << uint(len(bit))
+	// convert the gate to the State's precision once
+	u := make([]T, dim*dim)
+	for i := 0; i < dim; i++ {
+		for j := 0; j < dim; j++ {
+			u[i*dim+j] = T(input[i][j])
+		}
+	}
+	// offsets of each local basis state within the State index, and the mask of all gate bits
+	offset := make([]int, dim)
+	mask := 0
+	for m, b := range bit {
+		stride := 1 << uint(n-1-b)
+		mask |= stride
+		for l := 0; l < dim; l++ {
+			if l&(1<<uint(len(bit)-1-m)) != 0 {
+				offset[l] |= stride
+			}
+		}
+	}
+	local := make([]T, dim)
+	// for every index with all gate bits cleared, mix the amplitudes of its block
+	for base := 0; base < len(s.v); base++ {
+		if base&mask != 0 {
+			continue
+		}
+		for l := 0; l < dim; l++ {
+			local[l] = s.v[base|offset[l]]
+		}
+		for i := 0; i < dim; i++ {
+			var component T
+			for j := 0; j < dim; j++ {
+				component += u[i*dim+j] * local[j]
+			}
+			s.v[base|offset[i]] = component
+		}
+	}
+	return s
+}
+
+// MeasureAt : Returns the outcome, 0 or 1, of measuring the given bit and collapses the State
+// onto it. The outcome is zero when the random value, drawn from [0, 1) by the caller, is below
+// the probability of zero, so the caller controls the source of randomness
+func (s *State[T]) MeasureAt(bit int, random float64) (outcome int) {
+	n := s.NumberOfBit()
+	probability := s.Probability()
+	zero := 0.0
+	for i, p := range probability {
+		if BigEndian.Bit(i, bit, n) == 0 {
+			zero += p
+		}
+	}
+	if random >= zero {
+		outcome = 1
+	}
+	// clear every amplitude disagreeing with the outcome
+	for i := range s.v {
+		if BigEndian.Bit(i, bit, n) != outcome {
+			s.v[i] = 0
+		}
+	}
+	s.Normalise()
+	return
+}
+
+// ResetAt : Returns the current State with the given bit measured and flipped back to zero if
+// it was one, the random value choosing the outcome as in MeasureAt
+func (s *State[T]) ResetAt(bit int, random float64) *State[T] {
+	if s.MeasureAt(bit, random) == 0 {
+		return s
+	}
+	n := s.NumberOfBit()
+	stride := 1 << uint(n-1-bit)
+	for i := range s.v {
+		if i&stride == 0 {
+			s.v[i], s.v[i|stride] = s.v[i|stride], s.v[i]
+		}
+	}
+	return s
+}
@@ -0,0 +1,109 @@
+package qubit
+
+import (
+	"math"
+	"math/cmplx"
+	"math/rand"
+	"testing"
+
+	"github.com/benluxford/qe/gate"
+	"github.com/benluxford/qe/matrix"
+)
+
+// qft : Applies the quantum fourier transform to the State gate by gate, Hadamards and
+// controlled phases followed by the swaps reversing the bit order
+func qft[T Complex](s *State[T]) *State[T] {
+	n := s.NumberOfBit()
+	for i := 0; i < n; i++ {
+		s.ApplyAt(gate.H(), i)
+		for j, k := i+1, 2; j < n; j, k = j+1, k+1 {
+			s.ApplyAt(gate.CR(2, 0, 1, k), j, i)
+		}
+	}
+	for i := 0; i < n/2; i++ {
+		s.ApplyAt(gate.Swap(2, 0, 1), i, n-1-i)
+	}
+	return s
+}
+
+// grover : Runs the given number of Grover iterations searching for the marked index
+func grover[T Complex](n, marked, iterations int) *State[T] {
+	s := ZeroState[T](n)
+	hadamards := func() {
+		for i := 0; i < n; i++ {
+			s.ApplyAt(gate.H(), i)
+		}
+	}
+	bits := make([]int, n)
+	for i := range bits {
+		bits[i] = i
+	}
+	oracle, reflection := diagonal(1<<uint(n)), diagonal(1<<uint(n))
+	oracle[marked][marked] = -1
+	for i := range reflection {
+		reflection[i][i] = -1
+	}
+	reflection[0][0] = 1
+	hadamards()
+	for k := 0; k < iterations; k++ {
+		s.ApplyAt(oracle, bits...)
+		hadamards()
+		s.ApplyAt(reflection, bits...)
+		hadamards()
+	}
+	return s
+}
+
+// diagonal : Returns the identity of the given dimension
+func diagonal(dim int) matrix.Matrix {
+	m := make(matrix.Matrix, dim)
+	for i := range m {
+		m[i] = make([]complex128, dim)
+		m[i][i] = 1
+	}
+	return m
+}
+
+// largestDifference : Returns the largest difference between amplitudes of the two States
+func largestDifference(a *State[complex64], b *State[complex128]) (most float64) {
+	for i, x := range a.Amplitude() {
+		most = math.Max(most, cmplx.Abs(complex128(x)-b.Amplitude()[i]))
+	}
+	return
+}
+
+func TestStateQFTPrecision(t *testing.T) {
+	const n = 10
+	rng := rand.New(rand.NewSource(1))
+	amplitudes := make([]complex128, 1<<n)
+	for i := range amplitudes {
+		amplitudes[i] = complex(rng.NormFloat64(), rng.NormFloat64())
+	}
+	input := New(amplitudes...)
+	single, double := qft(FromQubit[complex64](input)), qft(FromQubit[complex128](input))
+	if d := largestDifference(single, double); d > 1e-4 {
+		t.Errorf("complex64 QFT is %g from complex128", d)
+	}
+	// the transform of a basis state x has amplitudes e^(2 pi i x y / N) / sqrt(N)
+	const x = 5
+	basis := qft(ZeroState[complex128](n).ApplyAt(gate.X(), 7).ApplyAt(gate.X(), 9))
+	for y, a := range basis.Amplitude() {
+		want := cmplx.Exp(complex(0, 2*math.Pi*x*float64(y)/(1<<n))) / complex(math.Sqrt(1<<n), 0)
+		if cmplx.Abs(a-want) > 1e-9 {
+			t.Fatalf("QFT |%d> amplitude %d is %v, want %v", x, y, a, want)
+		}
+	}
+}
+
+func TestStateGroverPrecision(t *testing.T) {
+	const n, marked = 10, 613
+	// the optimal number of iterations, pi/4 sqrt(N)
+	iterations := int(math.Pi / 4 * math.Sqrt(1<<n))
+	single, double := grover[complex64](n, marked, iterations), grover[complex128](n, marked, iterations)
+	if d := largestDifference(single, double); d > 1e-4 {
+		t.Errorf("complex64 Grover is %g from complex128", d)
+	}
+	if p := single.Probability()[marked]; p < 0.99 {
+		t.Errorf("complex64 Grover finds the marked item with probability %g", p)
+	}
+}
@@ -0,0 +1,142 @@
+package transpile
+
+import (
+	"sort"
+
+	"github.com/benluxford/qe/circuit"
+	"github.com/benluxford/qe/matrix"
+)
+
+// block : A run of operations being fused, on a sorted set of qubits
+type block struct {
+	qubits []int
+	ops    []circuit.Op
+	u      matrix.Matrix
+}
+
+// Fuse : Returns a Circuit where neighbouring operations acting on at most width qubits
+// between them are merged into single "Fused" gates, so each merged run is a single sweep
+// over the state when run. Operations wider than width, or without a Matrix, are kept as is
+func Fuse(c *circuit.Circuit, width int) (*circuit.Circuit, Report) {
+	fused := c.Empty()
+	// the open block on each qubit, at most one per qubit
+	open := map[int]*block{}
+	// open blocks in the order they were started, so the output is deterministic
+	order := []*block{}
+
+	// close : emits the blocks touching the given qubits
+	close := func(qubits []int) {
+		touched := touching(open, qubits)
+		for _, b := range order {
+			if !touched[b] {
+				continue
+			}
+			fused.Append(b.op())
+			for _, q := range b.qubits {
+				delete(open, q)
+			}
+		}
+		order = remove(order, touched)
+	}
+
+	for _, o := range c.Ops() {
+		qubits := o.Qubits()
+		// operations that can not be merged close everything they touch
+		if o.Matrix == nil || o.Condition != nil || len(qubits) > width {
+			close(qubits)
+			fused.Append(o)
+			continue
+		}
+		touched := touching(open, qubits)
+		// the qubits the merged block would cover
+		union := append([]int{}, qubits...)
+		for b := range touched {
+			union = append(union, b.qubits...)
+		}
+		union = unique(union)
+		if len(union) > width {
+			close(qubits)
+			touched, union = nil, unique(qubits)
+		}
+		// merge the touched blocks (disjoint, so their order is free) and the operation
+		merged := &block{qubits: union, u: identity(len(union))}
+		for _, b := range order {
+			if !touched[b] {
+				continue
+			}
+			merged.ops = append(merged.ops, b.ops...)
+			merged.u = merged.u.Apply(circuit.Expand(b.u, len(union), positions(union, b.qubits)...))
+		}
+		merged.ops = append(merged.ops, o)
+		merged.u = merged.u.Apply(circuit.Expand(o.Unitary(), len(union), positions(union, qubits)...))
+		order = append(remove(order, touched), merged)
+		for _, q := range union {
+			open[q] = merged
+		}
+	}
+	// emit whatever is still open
+	for _, b := range order {
+		fused.Append(b.op())
+	}
+	return fused, report("fuse", c, fused)
+}
+
+// op : Returns the single operation of the block, unchanged when nothing was merged
+func (b *block) op() circuit.Op {
+	if len(b.ops) == 1 {
+		return b.ops[0]
+	}
+	return circuit.Op{Name: "Fused", Targets: b.qubits, Matrix: b.u}
+}
+
+// touching : Returns the set of open blocks acting on any of the given qubits
+func touching(open map[int]*block, qubits []int) (touched map[*block]bool) {
+	touched = map[*block]bool{}
+	for _, q := range qubits {
+		if b, ok := open[q]; ok {
+			touched[b] = true
+		}
+	}
+	return
+}
+
+// remove : Returns the blocks that are not in the given set, keeping their order
+func remove(order []*block, drop map[*block]bool) (kept []*block) {
+	for _, b := range order {
+		if !drop[b] {
+			kept = append(kept, b)
+		}
+	}
+	return
+}
+
+// unique : Returns the sorted distinct qubits
+func unique(qubits []int) (distinct []int) {
+	seen := map[int]bool{}
+	for _, q := range qubits {
+		if !seen[q] {
+			seen[q] = true
+			distinct = append(distinct, q)
+		}
+	}
+	sort.Ints(distinct)
+	return
+}
+
+// positions : Returns the index of each qubit within the given set
+func positions(set, qubits []int) (index []int) {
+	for _, q := range qubits {
+		for i, s := range set {
+			if s == q {
+				index = append(index, i)
+				break
+			}
+		}
+	}
+	return
+}
+
+// identity : Returns the identity on the given number of bits
+func identity(bit int) matrix.Matrix {
+	return matrix.Identity(1 << uint(bit)).Matrix()
+}
@@ -0,0 +1,135 @@
+package transpile
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/benluxford/qe/circuit"
+)
+
+// randomCircuit : Returns a Circuit of random one, two and three bit gates
+func randomCircuit(bit, gates int, rng *rand.Rand) *circuit.Circuit {
+	c := circuit.New(bit)
+	for i := 0; i < gates; i++ {
+		q := rng.Perm(bit)
+		switch k := rng.Intn(9); {
+		case k == 0:
+			c.H(q[0])
+		case k == 1:
+			c.T(q[0])
+		case k == 2:
+			c.Y(q[0])
+		case k == 3:
+			c.U(q[0], rng.Float64(), rng.Float64(), rng.Float64(), rng.Float64())
+		case k == 4:
+			c.CNOT(q[0], q[1])
+		case k == 5:
+			c.CR(q[0], q[1], 2+rng.Intn(3))
+		case k == 6:
+			c.Swap(q[0], q[1])
+		case bit > 2 && k == 7:
+			c.Toffoli(q[0], q[1], q[2])
+		default:
+			c.S(q[0])
+		}
+	}
+	return c
+}
+
+func TestFuse(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 20; trial++ {
+		c := randomCircuit(4, 40, rng)
+		for width := 1; width <= 3; width++ {
+			fused, report := Fuse(c, width)
+			if !fused.Unitary().Equals(c.Unitary(), 1e-9) {
+				t.Fatalf("width %d: %v changed the unitary", width, report)
+			}
+			for _, o := range fused.Ops() {
+				if o.Name == "Fused" && len(o.Qubits()) > width {
+					t.Errorf("width %d: fused %v onto %d qubits", width, o.Qubits(), len(o.Qubits()))
+				}
+			}
+			if report.Before != c.Len() || report.After != fused.Len() {
+				t.Errorf("width %d: report %d -> %d, circuits %d -> %d", width, report.Before, report.After, c.Len(), fused.Len())
+			}
+			if width == 3 && fused.Len() >= c.Len() {
+				t.Errorf("width 3: nothing fused, %v", report)
+			}
+		}
+	}
+}
+
+func TestFuseReport(t *testing.T) {
+	c := circuit.New(3).H(0).H(0).X(1).CNOT(0, 1).T(2).Toffoli(0, 1, 2)
+	tests := []struct {
+		width  int
+		after  int
+		counts map[string]int
+	}{
+		// H H merge, every other gate stands alone or is too wide
+		{1, 5, map[string]int{"Fused": 1, "X": 1, "CX": 1, "T": 1, "CCX": 1}},
+		// H H X CX merge on qubits 0 and 1, T is left alone
+		{2, 3, map[string]int{"Fused": 1, "T": 1, "CCX": 1}},
+		// everything merges
+		{3, 1, map[string]int{"Fused": 1}},
+	}
+	for _, tt := range tests {
+		fused, report := Fuse(c, tt.width)
+		if report.Pass != "fuse" || report.Before != 6 || report.After != tt.after || fused.Len() != tt.after {
+			t.Errorf("width %d: got %v, want 6 -> %d", tt.width, report, tt.after)
+		}
+		want := map[string]int{"H": 2, "X": 1, "CX": 1, "T": 1, "CCX": 1}
+		for label, n := range want {
+			if report.BeforeCounts[label] != n {
+				t.Errorf("width %d: before counts %v, want %v", tt.width, report.BeforeCounts, want)
+				break
+			}
+		}
+		if len(report.AfterCounts) != len(tt.counts) {
+			t.Errorf("width %d: after counts %v, want %v", tt.width, report.AfterCounts, tt.counts)
+		}
+		for label, n := range tt.counts {
+			if report.AfterCounts[label] != n {
+				t.Errorf("width %d: after counts %v, want %v", tt.width, report.AfterCounts, tt.counts)
+				break
+			}
+		}
+	}
+}
+
+func TestFuseStopsAtNonGates(t *testing.T) {
+	tests := []struct {
+		name  string
+		input *circuit.Circuit
+		after int
+	}{
+		{"gates alone", circuit.New(1).H(0).T(0).H(0), 1},
+		{"measure", circuit.New(1).H(0).Measure(0, 0).H(0).T(0), 3},
+		{"reset", circuit.New(1).H(0).T(0).Reset(0).H(0), 3},
+		{"condition", circuit.New(1).H(0).Measure(0, 0).H(0).If([]int{0}, 1, circuit.New(1).X(0)).T(0), 5},
+		{"other qubit", circuit.New(2).H(0).Measure(1, 0).T(0), 2},
+	}
+	for _, tt := range tests {
+		fused, _ := Fuse(tt.input, 1)
+		if fused.Len() != tt.after {
+			t.Errorf("%s: got %v, want %d ops", tt.name, fused.Ops(), tt.after)
+		}
+		// the operations that are not gates keep their place relative to each other
+		kept := []string{}
+		for _, o := range fused.Ops() {
+			if o.Matrix == nil || o.Condition != nil {
+				kept = append(kept, o.Label())
+			}
+		}
+		want := []string{}
+		for _, o := range tt.input.Ops() {
+			if o.Matrix == nil || o.Condition != nil {
+				want = append(want, o.Label())
+			}
+		}
+		if len(kept) != len(want) {
+			t.Errorf("%s: kept %v, want %v", tt.name, kept, want)
+		}
+	}
+}
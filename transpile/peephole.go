@@ -0,0 +1,224 @@
+package transpile
+
+import (
+	"math"
+
+	"github.com/benluxford/qe/circuit"
+	"github.com/benluxford/qe/gate"
+)
+
+// angleEps : How close to zero a merged angle must be for its gate to be dropped
+const angleEps = 1e-9
+
+// Peephole : Returns a Circuit with identities dropped, inverse pairs cancelled, rotations
+// merged and diagonal gates commuted past each other to expose more of both. The result
+// equals the input up to a global phase
+func Peephole(c *circuit.Circuit) (*circuit.Circuit, Report) {
+	ops := c.Ops()
+	// repeat until a sweep changes nothing, a merge can expose a new cancellation
+	for changed := true; changed; {
+		ops, changed = peephole(ops)
+	}
+	optimised := c.Empty().Append(ops...)
+	return optimised, report("peephole", c, optimised)
+}
+
+// peephole : Returns the operations after a single sweep of the rules and whether anything changed
+func peephole(input []circuit.Op) (ops []circuit.Op, changed bool) {
+	for _, o := range input {
+		if droppable(o) {
+			changed = true
+			continue
+		}
+		consumed := false
+		// look back for a partner, skipping anything the operation commutes with
+		for j := len(ops) - 1; j >= 0; j-- {
+			p := ops[j]
+			if disjoint(p, o) {
+				continue
+			}
+			// a conditioned operation may not run, so nothing cancels or merges with it
+			if p.Condition != nil || o.Condition != nil {
+				break
+			}
+			if inverse(p, o) {
+				ops = append(ops[:j], ops[j+1:]...)
+				consumed = true
+				break
+			}
+			if merged, ok := merge(p, o); ok {
+				if droppable(merged) {
+					ops = append(ops[:j], ops[j+1:]...)
+				} else {
+					ops[j] = merged
+				}
+				consumed = true
+				break
+			}
+			if p.Matrix == nil || o.Matrix == nil || !diagonal(p) || !diagonal(o) {
+				break
+			}
+		}
+		if consumed {
+			changed = true
+			continue
+		}
+		ops = append(ops, o)
+	}
+	return
+}
+
+// droppable : Returns true if the operation is an identity, up to a global phase when uncontrolled.
+// The rules below only trust an operation's name when its matrix is the gate the name stands for
+func droppable(o circuit.Op) bool {
+	if !o.Standard() {
+		return false
+	}
+	switch o.Name {
+	case "I":
+		return true
+	case "P":
+		return zero(o.Params[0], 2*math.Pi)
+	case "RX", "RY", "RZ":
+		// a full turn is -I, only a global phase without controls
+		if len(o.Controls) == 0 {
+			return zero(o.Params[0], 2*math.Pi)
+		}
+		return zero(o.Params[0], 4*math.Pi)
+	}
+	return false
+}
+
+// inverse : Returns true if the two operations cancel each other exactly
+func inverse(p, o circuit.Op) bool {
+	names := map[string]string{
+		"H": "H", "X": "X", "Y": "Y", "Z": "Z", "Swap": "Swap",
+		"S": "Sdg", "Sdg": "S", "T": "Tdg", "Tdg": "T",
+	}
+	if names[p.Name] != o.Name || !sameSet(p.Controls, o.Controls) || !p.Standard() || !o.Standard() {
+		return false
+	}
+	// a swap is symmetric in its targets
+	if o.Name == "Swap" {
+		return sameSet(p.Targets, o.Targets)
+	}
+	return sameList(p.Targets, o.Targets)
+}
+
+// merge : Returns a single operation equal to p followed by o, if both are the same kind of rotation
+func merge(p, o circuit.Op) (circuit.Op, bool) {
+	if !p.Standard() || !o.Standard() {
+		return circuit.Op{}, false
+	}
+	// phase gates are symmetric in all their qubits, so only the set has to match
+	a, okA := phase(p)
+	b, okB := phase(o)
+	if okA && okB && sameSet(p.Qubits(), o.Qubits()) {
+		return phaseOp(o.Controls, o.Targets, a+b), true
+	}
+	if !sameSet(p.Controls, o.Controls) || !sameList(p.Targets, o.Targets) {
+		return circuit.Op{}, false
+	}
+	// rotations about the same axis add
+	if p.Name == o.Name && (o.Name == "RX" || o.Name == "RY" || o.Name == "RZ") {
+		theta := p.Params[0] + o.Params[0]
+		merged := circuit.Op{Name: o.Name, Controls: o.Controls, Targets: o.Targets, Params: []float64{theta}}
+		switch o.Name {
+		case "RX":
+			merged.Matrix = gate.RX(theta)
+		case "RY":
+			merged.Matrix = gate.RY(theta)
+		default:
+			merged.Matrix = gate.RZ(theta)
+		}
+		return merged, true
+	}
+	return circuit.Op{}, false
+}
+
+// phase : Returns phi if the operation is the phase gate diag(1, e^(i phi)) on its target
+func phase(o circuit.Op) (float64, bool) {
+	if len(o.Targets) != 1 || !o.Standard() {
+		return 0, false
+	}
+	switch o.Name {
+	case "Z":
+		return math.Pi, true
+	case "S":
+		return math.Pi / 2, true
+	case "Sdg":
+		return -math.Pi / 2, true
+	case "T":
+		return math.Pi / 4, true
+	case "Tdg":
+		return -math.Pi / 4, true
+	case "P":
+		return o.Params[0], true
+	case "R":
+		return 2 * math.Pi / math.Pow(2, o.Params[0]), true
+	}
+	return 0, false
+}
+
+// phaseOp : Returns the phase gate for phi, using the named gate when there is one
+func phaseOp(controls, targets []int, phi float64) circuit.Op {
+	// bring phi into (-pi, pi]
+	phi = math.Remainder(phi, 2*math.Pi)
+	if phi <= -math.Pi+angleEps {
+		phi += 2 * math.Pi
+	}
+	named := []struct {
+		name string
+		phi  float64
+	}{
+		{"Z", math.Pi}, {"S", math.Pi / 2}, {"Sdg", -math.Pi / 2}, {"T", math.Pi / 4}, {"Tdg", -math.Pi / 4},
+	}
+	for _, n := range named {
+		if math.Abs(phi-n.phi) < angleEps {
+			return circuit.Op{Name: n.name, Controls: controls, Targets: targets, Matrix: gate.P(n.phi)}
+		}
+	}
+	return circuit.Op{Name: "P", Controls: controls, Targets: targets, Params: []float64{phi}, Matrix: gate.P(phi)}
+}
+
+// diagonal : Returns true if the operation is diagonal in the computational basis
+func diagonal(o circuit.Op) bool {
+	if (o.Name == "RZ" || o.Name == "I") && o.Standard() {
+		return true
+	}
+	_, ok := phase(o)
+	return ok
+}
+
+// disjoint : Returns true if the two operations share no qubits
+func disjoint(p, o circuit.Op) bool {
+	for _, q := range o.Qubits() {
+		if p.Acts(q) {
+			return false
+		}
+	}
+	return true
+}
+
+// zero : Returns true if the angle is a multiple of the period
+func zero(angle, period float64) bool {
+	return math.Abs(math.Remainder(angle, period)) < angleEps
+}
+
+// sameList : Returns true if both slices hold the same qubits in the same order
+func sameList(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// sameSet : Returns true if both slices hold the same qubits in any order
+func sameSet(a, b []int) bool {
+	return len(a) == len(b) && sameList(unique(a), unique(b))
+}
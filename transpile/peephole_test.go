@@ -0,0 +1,95 @@
+package transpile
+
+import (
+	"math"
+	"testing"
+
+	"github.com/benluxford/qe/circuit"
+	"github.com/benluxford/qe/gate"
+)
+
+func TestPeephole(t *testing.T) {
+	tests := []struct {
+		name  string
+		input *circuit.Circuit
+		after int
+	}{
+		{"identity", circuit.New(1).I(0).H(0), 1},
+		{"H H", circuit.New(1).H(0).H(0), 0},
+		{"X X", circuit.New(1).X(0).X(0), 0},
+		{"CNOT CNOT", circuit.New(2).CNOT(0, 1).CNOT(0, 1), 0},
+		{"S Sdg", circuit.New(1).S(0).Sdg(0), 0},
+		{"T Tdg", circuit.New(1).T(0).Tdg(0), 0},
+		{"Swap reversed", circuit.New(2).Swap(0, 1).Swap(1, 0), 0},
+		{"RX merge", circuit.New(1).RX(0, 0.2).RX(0, 0.5), 1},
+		{"RZ cancel", circuit.New(1).RZ(0, 0.3).RZ(0, -0.3), 0},
+		{"RY full turn", circuit.New(1).RY(0, 2*math.Pi), 0},
+		{"controlled RZ full turn kept", circuit.New(2).Append(circuit.Op{Name: "RZ", Controls: []int{0}, Targets: []int{1}, Params: []float64{2 * math.Pi}, Matrix: gate.RZ(2 * math.Pi)}), 1},
+		{"S S is Z", circuit.New(1).S(0).S(0), 1},
+		{"phases merge", circuit.New(1).T(0).P(0, 0.4).R(0, 3), 1},
+		{"Z commutes past CZ", circuit.New(2).Z(0).CZ(0, 1).Z(0), 1},
+		{"T commutes past CS", circuit.New(2).T(1).CS(0, 1).Tdg(1), 1},
+		{"CZ symmetric", circuit.New(2).CZ(0, 1).CZ(1, 0), 0},
+		{"blocked by H", circuit.New(2).Z(0).H(0).Z(0), 3},
+		{"blocked by CNOT", circuit.New(2).X(1).CNOT(0, 1).H(1).X(1), 4},
+		{"disjoint skipped", circuit.New(2).H(0).X(1).H(0), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			optimised, report := Peephole(tt.input)
+			if optimised.Len() != tt.after {
+				t.Errorf("%v: got %d ops %v, want %d", report, optimised.Len(), optimised.Ops(), tt.after)
+			}
+			if !optimised.Unitary().EqualsUpToPhase(tt.input.Unitary(), 1e-9) {
+				t.Errorf("%v: unitary changed", optimised.Ops())
+			}
+		})
+	}
+}
+
+func TestPeepholeTrustsOnlyStandardGates(t *testing.T) {
+	tests := []struct {
+		name  string
+		input *circuit.Circuit
+	}{
+		{"H holding S", circuit.New(1).Gate("H", gate.S(), 0).Gate("H", gate.S(), 0)},
+		{"S holding T", circuit.New(1).Gate("S", gate.T(), 0).Sdg(0)},
+		{"RZ without params", circuit.New(1).Gate("RZ", gate.RZ(0.3), 0).RZ(0, 0.2)},
+		{"P without params", circuit.New(1).Gate("P", gate.P(0.3), 0).Gate("P", gate.P(0.3), 0)},
+		{"I holding X", circuit.New(1).Gate("I", gate.X(), 0)},
+		{"RX holding RY", circuit.New(1).Append(circuit.Op{Name: "RX", Targets: []int{0}, Params: []float64{0.5}, Matrix: gate.RY(0.5)}).RX(0, 0.5)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			optimised, _ := Peephole(tt.input)
+			if optimised.Len() != tt.input.Len() {
+				t.Errorf("got %v from %v", optimised.Ops(), tt.input.Ops())
+			}
+			if !optimised.Unitary().EqualsUpToPhase(tt.input.Unitary(), 1e-9) {
+				t.Errorf("%v: unitary changed", optimised.Ops())
+			}
+		})
+	}
+}
+
+func TestPeepholeRandom(t *testing.T) {
+	// long runs of gates that mostly cancel or merge, on few qubits so partners are common
+	c := circuit.New(3)
+	gates := []func(q, p int){
+		func(q, p int) { c.H(q) }, func(q, p int) { c.X(q) }, func(q, p int) { c.S(q) },
+		func(q, p int) { c.Sdg(q) }, func(q, p int) { c.T(q) }, func(q, p int) { c.Tdg(q) },
+		func(q, p int) { c.RZ(q, 0.1*float64(p+1)) }, func(q, p int) { c.RX(q, -0.2) },
+		func(q, p int) { c.CNOT(q, p) }, func(q, p int) { c.CZ(q, p) }, func(q, p int) { c.Swap(q, p) },
+	}
+	for i := 0; i < 200; i++ {
+		q := i * 7 % 3
+		gates[i*13%len(gates)](q, (q+1+i%2)%3)
+	}
+	optimised, report := Peephole(c)
+	if optimised.Len() >= c.Len() {
+		t.Errorf("nothing removed: %v", report)
+	}
+	if !optimised.Unitary().EqualsUpToPhase(c.Unitary(), 1e-8) {
+		t.Errorf("unitary changed: %v", report)
+	}
+}
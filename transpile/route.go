@@ -0,0 +1,257 @@
+package transpile
+
+import (
+	"fmt"
+
+	"github.com/benluxford/qe/circuit"
+	"github.com/benluxford/qe/coupling"
+)
+
+// extendedSize : How many upcoming two bit gates the router looks ahead at
+const extendedSize = 20
+
+// extendedWeight : How much the upcoming gates count against the current front
+const extendedWeight = 0.5
+
+// decayStep : How much a bit's score is penalised each time it is swapped, so the
+// router prefers spreading swaps over bouncing the same pair back and forth
+const decayStep = 0.001
+
+// Routing : The outcome of Route, layouts give the physical bit holding each logical bit
+type Routing struct {
+	Swaps   int
+	Initial []int
+	Final   []int
+}
+
+// String : Returns the Routing as e.g. "route: 3 swaps, layout [0 1 2] -> [1 0 2]"
+func (r Routing) String() string {
+	return fmt.Sprintf("route: %d swaps, layout %v -> %v", r.Swaps, r.Initial, r.Final)
+}
+
+// Route : Returns the Circuit mapped onto the physical bits of the device, with Swap
+// operations inserted so every two bit gate acts on connected bits. Swaps are chosen with
+// the SABRE heuristic: the swap minimising the distance of the gates waiting to run, and
+// to a lesser degree of the gates after them, is applied until a gate can run. Logical bit
+// i starts on physical bit i, gates on more than two bits must be translated first. The
+// classical registers are kept, the quantum registers are not as their bits move between
+// physical bits during the circuit, Routing gives where they start and end
+func Route(c *circuit.Circuit, device *coupling.Map) (*circuit.Circuit, Routing, error) {
+	n, size := c.NumberOfBit(), device.NumberOfBit()
+	if n > size {
+		return nil, Routing{}, fmt.Errorf("transpile: %d bits do not fit on a %d bit device", n, size)
+	}
+	for i := 1; i < size; i++ {
+		if device.Distance(0, i) < 0 {
+			return nil, Routing{}, fmt.Errorf("transpile: bit %d is not connected to the device", i)
+		}
+	}
+	ops := c.Ops()
+	for _, o := range ops {
+		if o.Matrix != nil && len(o.Qubits()) > 2 {
+			return nil, Routing{}, fmt.Errorf("transpile: %v acts on more than two bits, translate it first", o)
+		}
+	}
+
+	// the layout holds every physical bit, logical bits past n are spare
+	position := make([]int, size)
+	for i := range position {
+		position[i] = i
+	}
+	routing := Routing{Initial: append([]int{}, position[:n]...)}
+
+	// dependencies between operations sharing a bit, quantum or classical
+	waiting := make([]int, len(ops))
+	next := make([][]int, len(ops))
+	last := map[int]int{}
+	for i, o := range ops {
+		for _, q := range resources(o) {
+			if p, ok := last[q]; ok {
+				next[p] = append(next[p], i)
+				waiting[i]++
+			}
+			last[q] = i
+		}
+	}
+	front := []int{}
+	for i := range ops {
+		if waiting[i] == 0 {
+			front = append(front, i)
+		}
+	}
+
+	routed := circuit.New(size)
+	for _, r := range c.ClassicalRegisters() {
+		// registers start at the first free classical bit, so one after unnamed bits cannot be placed
+		if routed.NumberOfClbit() != r.Start {
+			return nil, Routing{}, fmt.Errorf("transpile: classical register %v follows bits outside of any register", r)
+		}
+		routed.ClassicalRegister(r.Name, r.Size)
+	}
+	decay := make([]float64, size)
+	for i := range decay {
+		decay[i] = 1
+	}
+	stalled := 0
+	// distance : Returns how far apart the bits of a two bit gate currently are
+	distance := func(o circuit.Op) int {
+		q := o.Qubits()
+		return device.Distance(position[q[0]], position[q[1]])
+	}
+	// exchange : Exchanges the logical bits on two physical bits
+	exchange := func(a, b int) {
+		for logical, physical := range position {
+			switch physical {
+			case a:
+				position[logical] = b
+			case b:
+				position[logical] = a
+			}
+		}
+	}
+	// swap : Exchanges the logical bits on two physical bits and records it
+	swap := func(a, b int) {
+		exchange(a, b)
+		routed.Swap(a, b)
+		routing.Swaps++
+		decay[a] += decayStep
+		decay[b] += decayStep
+	}
+
+	for len(front) > 0 {
+		// run everything in the front that the layout allows
+		executed := false
+		remaining := []int{}
+		for _, i := range front {
+			o := ops[i]
+			if o.Matrix != nil && len(o.Qubits()) == 2 && distance(o) != 1 {
+				remaining = append(remaining, i)
+				continue
+			}
+			routed.Append(mapped(o, position))
+			executed = true
+			for _, j := range next[i] {
+				waiting[j]--
+				if waiting[j] == 0 {
+					remaining = append(remaining, j)
+				}
+			}
+		}
+		front = remaining
+		if executed {
+			for i := range decay {
+				decay[i] = 1
+			}
+			stalled = 0
+			continue
+		}
+
+		// when the heuristic goes round in circles, walk the first gate into place
+		stalled++
+		if stalled > 10*size {
+			q := ops[front[0]].Qubits()
+			path := device.Path(position[q[0]], position[q[1]])
+			for k := 0; k+2 < len(path); k++ {
+				swap(path[k], path[k+1])
+			}
+			stalled = 0
+			continue
+		}
+
+		// otherwise apply the best scoring swap next to a waiting gate
+		extended := lookahead(ops, front, next, waiting)
+		best, bestScore := [2]int{}, -1.0
+		for _, i := range front {
+			for _, q := range ops[i].Qubits() {
+				a := position[q]
+				for _, b := range device.Neighbours(a) {
+					// score a trial exchange, then undo it
+					exchange(a, b)
+					score := average(ops, front, distance) + extendedWeight*average(ops, extended, distance)
+					exchange(a, b)
+					if decay[a] > decay[b] {
+						score *= decay[a]
+					} else {
+						score *= decay[b]
+					}
+					if bestScore < 0 || score < bestScore {
+						best, bestScore = [2]int{a, b}, score
+					}
+				}
+			}
+		}
+		swap(best[0], best[1])
+	}
+
+	routing.Final = append([]int{}, position[:n]...)
+	return routed, routing, nil
+}
+
+// lookahead : Returns up to extendedSize two bit gates that follow the front
+func lookahead(ops []circuit.Op, front []int, next [][]int, waiting []int) (extended []int) {
+	// count down a copy of the dependencies as gates are visited in order
+	pending := map[int]int{}
+	queue := append([]int{}, front...)
+	for len(queue) > 0 && len(extended) < extendedSize {
+		i := queue[0]
+		queue = queue[1:]
+		for _, j := range next[i] {
+			if _, ok := pending[j]; !ok {
+				pending[j] = waiting[j]
+			}
+			pending[j]--
+			if pending[j] > 0 {
+				continue
+			}
+			if ops[j].Matrix != nil && len(ops[j].Qubits()) == 2 {
+				extended = append(extended, j)
+			}
+			queue = append(queue, j)
+		}
+	}
+	return
+}
+
+// average : Returns the mean distance of the two bit gates in the set
+func average(ops []circuit.Op, set []int, distance func(circuit.Op) int) float64 {
+	sum, count := 0, 0
+	for _, i := range set {
+		if ops[i].Matrix != nil && len(ops[i].Qubits()) == 2 {
+			sum += distance(ops[i])
+			count++
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	return float64(sum) / float64(count)
+}
+
+// resources : Returns the bits an operation depends on, its qubits and any classical bits it
+// writes or reads
+func resources(o circuit.Op) (bits []int) {
+	bits = o.Qubits()
+	clbits := append([]int{}, o.Clbits...)
+	if o.Condition != nil {
+		clbits = append(clbits, o.Condition.Clbits...)
+	}
+	// classical bit b is kept apart from the qubits as -1 - b
+	for _, b := range clbits {
+		bits = append(bits, -1-b)
+	}
+	return
+}
+
+// mapped : Returns the operation acting on the physical bits of the layout
+func mapped(o circuit.Op, position []int) circuit.Op {
+	controls := make([]int, len(o.Controls))
+	for i, q := range o.Controls {
+		controls[i] = position[q]
+	}
+	targets := make([]int, len(o.Targets))
+	for i, q := range o.Targets {
+		targets[i] = position[q]
+	}
+	o.Controls, o.Targets = controls, targets
+	return o
+}
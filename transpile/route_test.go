@@ -0,0 +1,115 @@
+package transpile
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/benluxford/qe/circuit"
+	"github.com/benluxford/qe/coupling"
+	"github.com/benluxford/qe/qubit"
+)
+
+func TestRouteKeepsClassicalRegisters(t *testing.T) {
+	// a GHZ state across the ends of a line, read into two registers
+	c := circuit.New(0)
+	data := c.QuantumRegister("data", 3)
+	first := c.ClassicalRegister("first", 1)
+	rest := c.ClassicalRegister("rest", 2)
+	c.H(data.At(0)).CNOT(data.At(0), data.At(2)).CNOT(data.At(2), data.At(1))
+	c.Measure(data.At(0), first.At(0)).Measure(data.At(1), rest.At(0)).Measure(data.At(2), rest.At(1))
+
+	routed, routing, err := Route(c, coupling.Line(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if routing.Swaps == 0 {
+		t.Errorf("%v: expected a swap", routing)
+	}
+	if got := routed.ClassicalRegisters(); len(got) != 2 || got[0] != first || got[1] != rest {
+		t.Errorf("got registers %v, want %v %v", got, first, rest)
+	}
+	result := routed.Execute(nil, 100, rand.New(rand.NewSource(1)))
+	counts, err := result.Register("rest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if counts["00"]+counts["11"] != 100 || counts["00"] == 0 || counts["11"] == 0 {
+		t.Errorf("got %v in rest, want only 00 and 11", counts)
+	}
+}
+
+func TestRouteRejectsRegisterAfterUnnamedBits(t *testing.T) {
+	c := circuit.New(2).Measure(0, 0)
+	c.ClassicalRegister("out", 1)
+	c.Measure(1, 1)
+	if _, _, err := Route(c, coupling.Line(2)); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestRouteRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	devices := []struct {
+		name   string
+		device *coupling.Map
+	}{
+		{"line", coupling.Line(5)},
+		{"grid", coupling.Grid(2, 3)},
+		{"heavy hex", coupling.HeavyHex(2, 3)},
+	}
+	for _, d := range devices {
+		swaps := 0
+		for trial := 0; trial < 5; trial++ {
+			n, size := 5, d.device.NumberOfBit()
+			c := circuit.New(n)
+			for i := 0; i < 30; i++ {
+				q := rng.Perm(n)
+				switch rng.Intn(5) {
+				case 0:
+					c.H(q[0])
+				case 1:
+					c.T(q[0])
+				case 2:
+					c.CZ(q[0], q[1])
+				case 3:
+					c.CR(q[0], q[1], 2)
+				default:
+					c.CNOT(q[0], q[1])
+				}
+			}
+			routed, routing, err := Route(c, d.device)
+			if err != nil {
+				t.Fatalf("%s: %v", d.name, err)
+			}
+			swaps += routing.Swaps
+			for _, o := range routed.Ops() {
+				if q := o.Qubits(); len(q) == 2 && !d.device.Connected(q[0], q[1]) {
+					t.Errorf("%s: %v is not on an edge", d.name, o)
+				}
+			}
+			// logical bit i ends on physical bit routing.Final[i], spare bits stay in zero
+			amplitudes := make([]complex128, 1<<uint(n))
+			for i := range amplitudes {
+				amplitudes[i] = complex(rng.NormFloat64(), rng.NormFloat64())
+			}
+			input := qubit.New(amplitudes...)
+			padded := make([]complex128, 1<<uint(size))
+			want := make([]complex128, 1<<uint(size))
+			output := c.Run(input.Clone()).Amplitude()
+			for x, a := range input.Amplitude() {
+				padded[x<<uint(size-n)] = a
+				y := 0
+				for i := 0; i < n; i++ {
+					y |= (x >> uint(n-1-i) & 1) << uint(size-1-routing.Final[i])
+				}
+				want[y] = output[x]
+			}
+			if got := routed.Run(qubit.New(padded...)); !got.Equals(qubit.New(want...), 1e-9) {
+				t.Errorf("%s: %v, the routed circuit is not the original up to the final layout", d.name, routing)
+			}
+		}
+		if swaps == 0 {
+			t.Errorf("%s: no swaps were needed, the test proves nothing", d.name)
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package transpile
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+
+	"github.com/benluxford/qe/circuit"
+	"github.com/benluxford/qe/decompose"
+	"github.com/benluxford/qe/gate"
+	"github.com/benluxford/qe/matrix"
+)
+
+// Basis : The gates a target supports, by Op label, e.g. RZ, SX and CX
+type Basis []string
+
+var (
+	// RZSXCX : The basis of superconducting devices with a CNOT entangler
+	RZSXCX = Basis{"RZ", "SX", "CX"}
+	// U3CZ : The basis of arbitrary single bit rotations with a CZ entangler
+	U3CZ = Basis{"U3", "CZ"}
+)
+
+// has : Returns true if the label is part of the Basis
+func (b Basis) has(label string) bool {
+	for _, name := range b {
+		if name == label {
+			return true
+		}
+	}
+	return false
+}
+
+// Translate : Returns a Circuit using only the gates of the Basis, equal to the input up to
+// a global phase. Single bit gates are resynthesised from their matrix, multi bit gates are
+// lowered through CX (or CZ) and single bit gates, multi controlled gates go through
+// decompose.MultiControlled, any other two bit gate (such as the output of Fuse) goes
+// through its KAK decomposition, and any other wider gate through two level unitaries
+func Translate(c *circuit.Circuit, basis Basis) (*circuit.Circuit, Report, error) {
+	translated := c.Empty()
+	for _, o := range c.Ops() {
+		if err := basis.lower(translated, o); err != nil {
+			return nil, Report{}, err
+		}
+	}
+	return translated, report("translate", c, translated), nil
+}
+
+// Equivalent : Returns true if the two Circuits have the same unitary up to a global phase
+func Equivalent(a, b *circuit.Circuit, eps ...float64) bool {
+	return a.NumberOfBit() == b.NumberOfBit() && a.Unitary().EqualsUpToPhase(b.Unitary(), eps...)
+}
+
+// lower : Appends the operation to the output in terms of the Basis
+func (b Basis) lower(out *circuit.Circuit, o circuit.Op) error {
+	// operations without a matrix are not gates and pass straight through, the name of a gate
+	// is only trusted when its matrix is the gate it names, otherwise it is lowered from the matrix
+	named := o.Standard()
+	if o.Matrix == nil || named && b.has(o.Label()) {
+		out.Append(o)
+		return nil
+	}
+	if droppable(o) {
+		return nil
+	}
+	// a conditioned gate is lowered as is, with the condition on every gate it becomes
+	if o.Condition != nil {
+		body := out.Empty()
+		inner := o
+		inner.Condition = nil
+		if err := b.lower(body, inner); err != nil {
+			return err
+		}
+		out.If(o.Condition.Clbits, o.Condition.Value, body)
+		return nil
+	}
+	// the rewrite rules produce a small circuit which is lowered again
+	rewrite := circuit.New(out.NumberOfBit())
+	qubits := o.Qubits()
+	switch {
+	case len(qubits) == 1:
+		return b.single(out, o.Matrix, qubits[0])
+	case named && o.Name == "QFT" && len(o.Controls) == 0:
+		qft(rewrite, o.Targets)
+	case named && o.Name == "Swap" && len(o.Controls) == 0:
+		x, y := o.Targets[0], o.Targets[1]
+		rewrite.CNOT(x, y).CNOT(y, x).CNOT(x, y)
+	case named && o.Name == "Swap" && len(o.Controls) == 1:
+		x, y := o.Targets[0], o.Targets[1]
+		rewrite.CNOT(y, x).Toffoli(o.Controls[0], x, y).CNOT(y, x)
+	case named && o.Name == "X" && len(o.Controls) == 2:
+		decompose.Toffoli(rewrite, o.Controls[0], o.Controls[1], o.Targets[0])
+	case len(o.Controls) == 1 && len(o.Targets) == 1:
+		if err := b.controlled(rewrite, o); err != nil {
+			return err
+		}
+	case len(qubits) == 2:
+		// any other two bit gate is rebuilt from its matrix with at most three CX
+		synthesised, err := decompose.TwoQubit(o.Unitary(), out.NumberOfBit(), qubits[0], qubits[1])
+		if err != nil {
+			return err
+		}
+		rewrite = synthesised
+	case len(o.Targets) == 1:
+		// more controls reduce to Toffolis and singly controlled roots of the gate
+		synthesised, err := decompose.MultiControlled(o.Name, o.Matrix, out.NumberOfBit(), o.Controls, o.Targets[0], decompose.Ancillas{})
+		if err != nil {
+			return err
+		}
+		rewrite = synthesised
+	case len(o.Controls) == 0 && o.Matrix.Equals(gate.Toffoli(), angleEps):
+		rewrite.Toffoli(o.Targets[0], o.Targets[1], o.Targets[2])
+	case len(o.Controls) == 0 && o.Matrix.Equals(gate.Fredkin(), angleEps):
+		rewrite.Fredkin(o.Targets[0], o.Targets[1], o.Targets[2])
+	case len(o.Controls) == 0 && o.Matrix.Equals(gate.QFT(len(o.Targets)), angleEps):
+		qft(rewrite, o.Targets)
+	default:
+		// any other gate is a product of multi controlled single bit gates
+		unitary(rewrite, o.Unitary(), qubits)
+	}
+	for _, r := range rewrite.Ops() {
+		if err := b.lower(out, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// controlled : Appends a singly controlled single bit gate using the entangler of the Basis
+func (b Basis) controlled(rewrite *circuit.Circuit, o circuit.Op) error {
+	c, t := o.Controls[0], o.Targets[0]
+	named := o.Standard()
+	switch {
+	case !b.has("CX") && !b.has("CZ"):
+		return fmt.Errorf("transpile: basis %v has no CX or CZ to lower %v", b, o)
+	case named && o.Name == "X" && b.has("CZ"):
+		rewrite.H(t).CZ(c, t).H(t)
+	case named && o.Name == "Z" && b.has("CX"):
+		rewrite.H(t).CNOT(c, t).H(t)
+	case named && o.Name == "X":
+		// with only CX in the basis, the CX itself was already accepted
+		return fmt.Errorf("transpile: no rule to lower %v", o)
+	default:
+		// a phase gate is a two CX ladder
+		if phi, ok := phase(o); ok {
+			rewrite.P(c, phi/2).CNOT(c, t).P(t, -phi/2).CNOT(c, t).P(t, phi/2)
+			return nil
+		}
+		// anything else is e^(i alpha) A X B X C with ABC = I
+		alpha, beta, gamma, delta, err := decompose.ZYZ(o.Matrix)
+		if err != nil {
+			return err
+		}
+		rewrite.RZ(t, (beta-delta)/2).
+			CNOT(c, t).RZ(t, -(beta+delta)/2).RY(t, -gamma/2).
+			CNOT(c, t).RY(t, gamma/2).RZ(t, delta).
+			P(c, alpha)
+	}
+	return nil
+}
+
+// single : Appends a single bit gate synthesised from its matrix in the rotations of the Basis
+func (b Basis) single(out *circuit.Circuit, input matrix.Matrix, bit int) error {
+	_, beta, gamma, delta, err := decompose.ZYZ(input)
+	if err != nil {
+		return err
+	}
+	// input is Rz(delta) Ry(gamma) Rz(beta), up to phase, so Rz(beta) runs first
+	rewrite := circuit.New(out.NumberOfBit())
+	switch {
+	case b.has("U3"):
+		rewrite.U3(bit, gamma, delta, beta)
+	case b.has("RZ") && b.has("RY"):
+		rewrite.RZ(bit, beta).RY(bit, gamma).RZ(bit, delta)
+	case b.has("RZ") && b.has("RX"):
+		_, beta, gamma, delta, _ = decompose.ZXZ(input)
+		rewrite.RZ(bit, beta).RX(bit, gamma).RZ(bit, delta)
+	case b.has("RZ") && b.has("SX"):
+		// a diagonal gate needs no SX at all
+		if zero(gamma, 4*math.Pi) {
+			rewrite.RZ(bit, beta+delta)
+			break
+		}
+		rewrite.RZ(bit, beta).SX(bit).RZ(bit, gamma+math.Pi).SX(bit).RZ(bit, delta+math.Pi)
+	default:
+		return fmt.Errorf("transpile: basis %v can not express single bit gates", b)
+	}
+	for _, o := range rewrite.Ops() {
+		if !droppable(o) {
+			out.Append(o)
+		}
+	}
+	return nil
+}
+
+// qft : Appends the quantum fourier transform over the given bits, as gate.QFT builds it
+func qft(rewrite *circuit.Circuit, bit []int) {
+	n := len(bit)
+	for i := 0; i < n; i++ {
+		rewrite.H(bit[i])
+		k := 2
+		for j := i + 1; j < n; j++ {
+			rewrite.CR(bit[j], bit[i], k)
+			k++
+		}
+	}
+	for i := 0; i < n/2; i++ {
+		rewrite.Swap(bit[i], bit[n-1-i])
+	}
+}
+
+// unitary : Appends the gate u on the qubits, qubits[0] being its most significant bit, as a
+// product of two level unitaries. Rotations of two basis states at a time clear each column
+// of u below its diagonal and move the phase left on the diagonal down to the next state, so
+// u is the inverse of the rotations, appended in reverse, after a phase on the last state
+func unitary(rewrite *circuit.Circuit, u matrix.Matrix, qubits []int) {
+	// the rotations are applied to a copy of u
+	dim := len(u)
+	m := make(matrix.Matrix, dim)
+	for i := range u {
+		m[i] = append([]complex128{}, u[i]...)
+	}
+	type level struct {
+		s, t     int
+		rotation matrix.Matrix
+	}
+	levels := []level{}
+	rotate := func(s, t int, rotation matrix.Matrix) {
+		for j := range m {
+			m[s][j], m[t][j] = rotation[0][0]*m[s][j]+rotation[0][1]*m[t][j], rotation[1][0]*m[s][j]+rotation[1][1]*m[t][j]
+		}
+		levels = append(levels, level{s, t, rotation})
+	}
+	for j := 0; j < dim-1; j++ {
+		for i := j + 1; i < dim; i++ {
+			a, b := m[j][j], m[i][j]
+			if cmplx.Abs(b) < angleEps {
+				continue
+			}
+			norm := complex(math.Hypot(cmplx.Abs(a), cmplx.Abs(b)), 0)
+			rotate(j, i, matrix.Matrix{{cmplx.Conj(a) / norm, cmplx.Conj(b) / norm}, {-b / norm, a / norm}})
+		}
+		if phi := cmplx.Phase(m[j][j]); !zero(phi, 2*math.Pi) {
+			rotate(j, j+1, matrix.Matrix{{cmplx.Exp(complex(0, -phi)), 0}, {0, cmplx.Exp(complex(0, phi))}})
+		}
+	}
+	// every rotation has a determinant of one, so the last state is left with that of u
+	twoLevel(rewrite, matrix.Matrix{{1, 0}, {0, m[dim-1][dim-1]}}, dim-2, dim-1, qubits)
+	for k := len(levels) - 1; k >= 0; k-- {
+		twoLevel(rewrite, levels[k].rotation.Dagger(), levels[k].s, levels[k].t, qubits)
+	}
+}
+
+// twoLevel : Appends the gate acting as the 2x2 rotation on the basis states s and t, in that
+// order, and as the identity on every other state. CNOTs from the first bit the states differ
+// in clear the others, so t is s with only that bit flipped and the rotation is a single bit
+// gate on it controlled by every other bit holding its value in s
+func twoLevel(rewrite *circuit.Circuit, rotation matrix.Matrix, s, t int, qubits []int) {
+	if rotation.Equals(gate.I(), angleEps) {
+		return
+	}
+	n := len(qubits)
+	value := func(state, i int) int {
+		return state >> uint(n-1-i) & 1
+	}
+	pivot := -1
+	ladder := circuit.New(rewrite.NumberOfBit())
+	for i := 0; i < n; i++ {
+		switch {
+		case value(s, i) == value(t, i):
+		case pivot < 0:
+			pivot = i
+		default:
+			ladder.CNOT(qubits[pivot], qubits[i])
+		}
+	}
+	// the CNOTs fire on the value of the pivot in t, which leaves s alone
+	if value(t, pivot) == 0 && ladder.Len() > 0 {
+		ladder = circuit.New(rewrite.NumberOfBit()).X(qubits[pivot]).Compose(ladder).X(qubits[pivot])
+	}
+	controls, flips := []int{}, circuit.New(rewrite.NumberOfBit())
+	for i := 0; i < n; i++ {
+		if i == pivot {
+			continue
+		}
+		controls = append(controls, qubits[i])
+		if value(s, i) == 0 {
+			flips.X(qubits[i])
+		}
+	}
+	// with s on the one of the pivot the rotation is seen through an X
+	if value(s, pivot) == 1 {
+		rotation = matrix.Matrix{{rotation[1][1], rotation[1][0]}, {rotation[0][1], rotation[0][0]}}
+	}
+	rewrite.Compose(ladder).Compose(flips)
+	rewrite.Append(circuit.Op{Name: "U", Controls: controls, Targets: []int{qubits[pivot]}, Matrix: rotation})
+	rewrite.Compose(flips).Compose(ladder)
+}
@@ -0,0 +1,96 @@
+package transpile
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/benluxford/qe/circuit"
+	"github.com/benluxford/qe/gate"
+)
+
+func TestTranslate(t *testing.T) {
+	c := circuit.New(3).H(0).T(1).Sdg(2).SX(0).Y(1).RX(2, 0.3).RY(0, -1.1).U3(1, 0.2, 0.4, 0.6).
+		U(2, 0.1, 0.2, 0.3, 0.4).P(0, 0.7).R(1, 3).
+		CNOT(0, 1).CZ(1, 2).CS(2, 0).CR(0, 2, 3).Swap(0, 2).Toffoli(0, 1, 2).Fredkin(2, 0, 1).QFT(0, 1, 2)
+	c.Append(circuit.Op{Name: "RY", Controls: []int{1}, Targets: []int{0}, Params: []float64{0.8}, Matrix: gate.RY(0.8)})
+	// gates whose name is not the matrix they hold
+	mislabelled := []circuit.Op{
+		{Name: "H", Targets: []int{0}, Matrix: gate.T()},
+		{Name: "SX", Targets: []int{1}, Matrix: gate.H()},
+		{Name: "RZ", Targets: []int{2}, Params: []float64{0.2}, Matrix: gate.RX(0.5)},
+		{Name: "X", Controls: []int{0}, Targets: []int{1}, Matrix: gate.RX(0.3)},
+		{Name: "Z", Controls: []int{2}, Targets: []int{0}, Matrix: gate.H()},
+		{Name: "Swap", Targets: []int{0, 2}, Matrix: gate.CNOT(2, 0, 1)},
+		{Name: "X", Controls: []int{0, 1}, Targets: []int{2}, Matrix: gate.S()},
+	}
+	bases := []Basis{RZSXCX, U3CZ, {"RZ", "RY", "CX"}, {"RZ", "RX", "CZ"}}
+	for _, basis := range bases {
+		for _, input := range append([]*circuit.Circuit{c}, each(mislabelled)...) {
+			translated, _, err := Translate(input, basis)
+			if err != nil {
+				t.Fatalf("%v: %v", basis, err)
+			}
+			for _, o := range translated.Ops() {
+				if !basis.has(o.Label()) || !o.Standard() {
+					t.Errorf("%v: %v is not in the basis", basis, o)
+				}
+			}
+			if !Equivalent(translated, input, 1e-8) {
+				t.Errorf("%v: translation of %v changed the unitary", basis, input.Ops())
+			}
+		}
+	}
+}
+
+// each : Returns a three bit Circuit holding each operation alone
+func each(ops []circuit.Op) (circuits []*circuit.Circuit) {
+	for _, o := range ops {
+		circuits = append(circuits, circuit.New(3).Append(o))
+	}
+	return
+}
+
+func TestTranslateWideGates(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	tests := []struct {
+		name  string
+		input *circuit.Circuit
+	}{
+		{"Toffoli matrix", circuit.New(3).Gate("Toffoli", gate.Toffoli(), 0, 1, 2)},
+		{"Toffoli matrix reordered", circuit.New(4).Gate("Toffoli", gate.Toffoli(), 3, 0, 2)},
+		{"Fredkin matrix", circuit.New(3).Gate("Fredkin", gate.Fredkin(), 2, 0, 1)},
+		{"QFT matrix", circuit.New(4).Gate("Fourier", gate.QFT(4), 0, 1, 2, 3)},
+		{"random three bit", circuit.New(3).Gate("Random", randomCircuit(3, 30, rng).Unitary(), 1, 2, 0)},
+		{"random four bit", circuit.New(4).Gate("Random", randomCircuit(4, 40, rng).Unitary(), 0, 1, 2, 3)},
+		{"diagonal", circuit.New(3).Gate("CCZ", gate.Controlled(3, gate.Z(), []int{0, 1}, nil, 2), 0, 1, 2)},
+		{"controlled two bit", circuit.New(3).Append(circuit.Op{Name: "Random", Controls: []int{2}, Targets: []int{0, 1}, Matrix: randomCircuit(2, 20, rng).Unitary()})},
+	}
+	for _, basis := range []Basis{RZSXCX, U3CZ} {
+		for _, tt := range tests {
+			translated, _, err := Translate(tt.input, basis)
+			if err != nil {
+				t.Fatalf("%v %s: %v", basis, tt.name, err)
+			}
+			for _, o := range translated.Ops() {
+				if !basis.has(o.Label()) || !o.Standard() {
+					t.Errorf("%v %s: %v is not in the basis", basis, tt.name, o)
+				}
+			}
+			if !Equivalent(translated, tt.input, 1e-8) {
+				t.Errorf("%v %s: translation changed the unitary", basis, tt.name)
+			}
+		}
+	}
+	// the matrices of known gates are lowered as those gates are
+	for _, pair := range [][2]*circuit.Circuit{
+		{circuit.New(3).Gate("Toffoli", gate.Toffoli(), 0, 1, 2), circuit.New(3).Toffoli(0, 1, 2)},
+		{circuit.New(3).Gate("Fredkin", gate.Fredkin(), 0, 1, 2), circuit.New(3).Fredkin(0, 1, 2)},
+		{circuit.New(3).Gate("Fourier", gate.QFT(3), 0, 1, 2), circuit.New(3).QFT(0, 1, 2)},
+	} {
+		raw, _, _ := Translate(pair[0], RZSXCX)
+		named, _, _ := Translate(pair[1], RZSXCX)
+		if raw.Len() != named.Len() {
+			t.Errorf("%v lowered to %d gates, the named gate to %d", pair[0].Ops(), raw.Len(), named.Len())
+		}
+	}
+}
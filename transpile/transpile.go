@@ -0,0 +1,48 @@
+package transpile
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/benluxford/qe/circuit"
+)
+
+// Report : The number of operations before and after a pass, in total and by label
+type Report struct {
+	Pass          string
+	Before, After int
+	BeforeCounts  map[string]int
+	AfterCounts   map[string]int
+}
+
+// report : Returns the Report comparing the input and output Circuit of a pass
+func report(pass string, before, after *circuit.Circuit) Report {
+	return Report{
+		Pass:         pass,
+		Before:       before.Len(),
+		After:        after.Len(),
+		BeforeCounts: before.Counts(),
+		AfterCounts:  after.Counts(),
+	}
+}
+
+// String : Returns the Report as e.g. "fuse: 12 -> 3 (H: 4 -> 0, Fused: 0 -> 3)"
+func (r Report) String() string {
+	// collect every label from both sides
+	labels := []string{}
+	for label := range r.BeforeCounts {
+		labels = append(labels, label)
+	}
+	for label := range r.AfterCounts {
+		if _, ok := r.BeforeCounts[label]; !ok {
+			labels = append(labels, label)
+		}
+	}
+	sort.Strings(labels)
+	counts := []string{}
+	for _, label := range labels {
+		counts = append(counts, fmt.Sprintf("%s: %d -> %d", label, r.BeforeCounts[label], r.AfterCounts[label]))
+	}
+	return fmt.Sprintf("%s: %d -> %d (%s)", r.Pass, r.Before, r.After, strings.Join(counts, ", "))
+}
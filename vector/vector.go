@@ -138,7 +138,8 @@ func TensorProductN(input Vector, bit ...int) (product Vector) {
 	return
 }
 
-// TensorProduct : Returns the tensor product of a given set of vectors
+// TensorProduct : Returns the tensor product of a given set of vectors, the first vector
+// holds the most significant bits of the result (bit 0 in the order of package qubit)
 func TensorProduct(input ...Vector) (product Vector) {
 	// set the product to the first vector
 	product = input[0]
@@ -190,6 +191,22 @@ func (v Vector) Equals(input Vector, eps ...float64) (equals bool) {
 	return true
 }
 
+// EqualsUpToPhase : Return true if the input vector matches the current vector once a global
+// phase e^(i phi) is removed, the two vectors then describe the same physical state
+func (v Vector) EqualsUpToPhase(input Vector, eps ...float64) bool {
+	// if the vectors are of diff length, return false
+	if len(v) != len(input) {
+		return false
+	}
+	// the phase that best aligns the two is the argument of their inner product
+	overlap := v.InnerProduct(input)
+	// with no overlap the only way to match is for both to be (near) zero
+	if overlap == 0 {
+		return v.Equals(input, eps...)
+	}
+	return v.Equals(input.Multiply(overlap/complex(cmplx.Abs(overlap), 0)), eps...)
+}
+
 // Dimension : Returns the length of the vector
 func (v Vector) Dimension() int {
 	return len(v)
@@ -0,0 +1,31 @@
+package vector
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+func TestEqualsUpToPhase(t *testing.T) {
+	plus := New(1/math.Sqrt2, 1/math.Sqrt2)
+	tests := []struct {
+		name     string
+		v, input Vector
+		want     bool
+	}{
+		{"identical", plus, plus, true},
+		{"global phase", plus, plus.Multiply(cmplx.Exp(1.3i)), true},
+		{"minus one", plus, plus.Multiply(-1), true},
+		{"relative phase", plus, New(1/math.Sqrt2, -1/math.Sqrt2), false},
+		{"imaginary relative phase", plus, New(1/math.Sqrt2, 1i/math.Sqrt2), false},
+		{"orthogonal", New(1, 0), New(0, 1), false},
+		{"scaled", plus, plus.Multiply(2), false},
+		{"both zero", New(0, 0), New(0, 0), true},
+		{"different length", plus, New(1, 0, 0, 0), false},
+	}
+	for _, tt := range tests {
+		if got := tt.v.EqualsUpToPhase(tt.input, 1e-12); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}